@@ -0,0 +1,168 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/BurntSushi/toml"
+
+	"github.com/laydros/fnorm/internal/normalize"
+)
+
+// configFileName is the name .fnorm.toml discovery looks for when
+// -config isn't given explicitly.
+const configFileName = ".fnorm.toml"
+
+// fileConfig is the on-disk shape of a .fnorm.toml file: zero or more
+// named profiles under [profiles.<name>].
+type fileConfig struct {
+	Profiles map[string]profileConfig `toml:"profiles"`
+}
+
+// profileConfig mirrors normalize.Profile, but every field is optional so
+// a profile can override only the settings it cares about on top of
+// normalize.DefaultProfile().
+type profileConfig struct {
+	Separator             *string           `toml:"separator"`
+	LowerCase             *bool             `toml:"lowercase"`
+	AllowedChars          *string           `toml:"allowed_chars"`
+	SpecialReplacements   map[string]string `toml:"special_replacements"`
+	MaxLength             *int              `toml:"max_length"`
+	Transliterate         *string           `toml:"transliterate"`
+	PreserveExtensionCase *bool             `toml:"preserve_extension_case"`
+	TrimChars             *string           `toml:"trim_chars"`
+}
+
+// apply overlays the fields set in c onto base and returns the result.
+func (c profileConfig) apply(base normalize.Profile) (normalize.Profile, error) {
+	if c.Separator != nil {
+		base.Separator = *c.Separator
+	}
+	if c.LowerCase != nil {
+		base.LowerCase = *c.LowerCase
+	}
+	if c.AllowedChars != nil {
+		base.AllowedChars = *c.AllowedChars
+	}
+	if c.SpecialReplacements != nil {
+		base.SpecialReplacements = c.SpecialReplacements
+	}
+	if c.MaxLength != nil {
+		base.MaxLength = *c.MaxLength
+	}
+	if c.PreserveExtensionCase != nil {
+		base.PreserveExtensionCase = *c.PreserveExtensionCase
+	}
+	if c.TrimChars != nil {
+		base.TrimChars = *c.TrimChars
+	}
+	if c.Transliterate != nil {
+		mode, err := parseTransliterateMode(*c.Transliterate)
+		if err != nil {
+			return normalize.Profile{}, err
+		}
+		base.Transliterate = mode
+	}
+	return base, nil
+}
+
+// parseTransliterateMode maps the config file's transliterate strings
+// onto normalize.Mode values.
+func parseTransliterateMode(name string) (normalize.Mode, error) {
+	switch name {
+	case "ascii-fold":
+		return normalize.ModeASCIIFold, nil
+	case "preserve-script":
+		return normalize.ModePreserveScript, nil
+	case "strip-non-ascii":
+		return normalize.ModeStripNonASCII, nil
+	default:
+		return 0, fmt.Errorf("unknown transliterate mode %q (want ascii-fold, preserve-script, or strip-non-ascii)", name)
+	}
+}
+
+// loadProfile reads path and returns the profile named by profileName,
+// overlaid onto normalize.DefaultProfile(). An empty profileName selects
+// "default".
+func loadProfile(path, profileName string) (normalize.Profile, error) {
+	var cfg fileConfig
+	if _, err := toml.DecodeFile(path, &cfg); err != nil {
+		return normalize.Profile{}, fmt.Errorf("load config %s: %w", path, err)
+	}
+
+	if profileName == "" {
+		profileName = "default"
+	}
+
+	pc, ok := cfg.Profiles[profileName]
+	if !ok {
+		return normalize.Profile{}, fmt.Errorf("profile %q not found in %s", profileName, path)
+	}
+
+	return pc.apply(normalize.DefaultProfile())
+}
+
+// findConfigFile walks upward from the directory containing target
+// looking for .fnorm.toml, mirroring how editorconfig and prettier
+// resolve their config files. It returns ok == false if none is found
+// before reaching the filesystem root.
+func findConfigFile(target string) (path string, ok bool) {
+	dir, err := filepath.Abs(filepath.Dir(target))
+	if err != nil {
+		return "", false
+	}
+
+	for {
+		candidate := filepath.Join(dir, configFileName)
+		if _, err := os.Stat(candidate); err == nil {
+			return candidate, true
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", false
+		}
+		dir = parent
+	}
+}
+
+// xdgConfigFile returns the user-level fallback config file,
+// $XDG_CONFIG_HOME/fnorm/config.toml (or ~/.config/fnorm/config.toml if
+// XDG_CONFIG_HOME isn't set), for when no .fnorm.toml is found walking
+// up from a target. It returns ok == false if that file doesn't exist.
+func xdgConfigFile() (path string, ok bool) {
+	base := os.Getenv("XDG_CONFIG_HOME")
+	if base == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", false
+		}
+		base = filepath.Join(home, ".config")
+	}
+
+	candidate := filepath.Join(base, "fnorm", "config.toml")
+	if _, err := os.Stat(candidate); err != nil {
+		return "", false
+	}
+	return candidate, true
+}
+
+// resolveProfile determines the normalize.Profile to use for target: an
+// explicit -config flag wins, otherwise .fnorm.toml is discovered upward
+// from target's directory, otherwise the XDG user config is tried,
+// otherwise normalize.DefaultProfile() applies.
+func resolveProfile(target string) (normalize.Profile, error) {
+	path := *configPath
+	if path == "" {
+		found, ok := findConfigFile(target)
+		if !ok {
+			found, ok = xdgConfigFile()
+		}
+		if !ok {
+			return normalize.DefaultProfile(), nil
+		}
+		path = found
+	}
+	return loadProfile(path, *profileName)
+}
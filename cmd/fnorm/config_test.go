@@ -0,0 +1,74 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadProfileAppliesTrimChars(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, ".fnorm.toml")
+	toml := `
+[profiles.default]
+trim_chars = "_"
+`
+	if err := os.WriteFile(path, []byte(toml), 0644); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+
+	profile, err := loadProfile(path, "")
+	if err != nil {
+		t.Fatalf("loadProfile failed: %v", err)
+	}
+	if profile.TrimChars != "_" {
+		t.Fatalf("expected TrimChars %q, got %q", "_", profile.TrimChars)
+	}
+}
+
+func TestXdgConfigFileFallsBackWhenUnset(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", "")
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	if _, ok := xdgConfigFile(); ok {
+		t.Fatalf("expected no XDG config file before one is created")
+	}
+
+	configDir := filepath.Join(home, ".config", "fnorm")
+	if err := os.MkdirAll(configDir, 0755); err != nil {
+		t.Fatalf("failed to create config dir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(configDir, "config.toml"), []byte("[profiles.default]\n"), 0644); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+
+	path, ok := xdgConfigFile()
+	if !ok {
+		t.Fatal("expected an XDG config file once one exists under ~/.config/fnorm")
+	}
+	if path != filepath.Join(configDir, "config.toml") {
+		t.Fatalf("unexpected XDG config path: %q", path)
+	}
+}
+
+func TestXdgConfigFileHonorsXDGConfigHome(t *testing.T) {
+	xdgHome := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", xdgHome)
+
+	configDir := filepath.Join(xdgHome, "fnorm")
+	if err := os.MkdirAll(configDir, 0755); err != nil {
+		t.Fatalf("failed to create config dir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(configDir, "config.toml"), []byte("[profiles.default]\n"), 0644); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+
+	path, ok := xdgConfigFile()
+	if !ok {
+		t.Fatal("expected an XDG config file under $XDG_CONFIG_HOME/fnorm")
+	}
+	if path != filepath.Join(configDir, "config.toml") {
+		t.Fatalf("unexpected XDG config path: %q", path)
+	}
+}
@@ -1,6 +1,3 @@
-//go:build integration
-// +build integration
-
 package main
 
 import (
@@ -12,36 +9,16 @@ import (
 	"testing"
 )
 
-var binaryPath string
-
-func TestMain(m *testing.M) {
-	// Build the binary once before running tests
-	tempDir, err := os.MkdirTemp("", "fnorm-test-*")
-	if err != nil {
-		panic(err)
-	}
-	defer os.RemoveAll(tempDir)
-
-	binaryPath = filepath.Join(tempDir, "fnorm")
-
-	// Build the binary - build the current directory (cmd/fnorm)
-	cmd := exec.Command("go", "build", "-o", binaryPath, ".")
-	output, err := cmd.CombinedOutput()
-	if err != nil {
-		panic(string(output) + ": " + err.Error())
-	}
-
-	// Run tests
-	exitCode := m.Run()
-
-	// Clean up
-	os.Remove(binaryPath)
-	os.Exit(exitCode)
-}
-
-// runFnorm executes the fnorm binary with given arguments
-func runFnorm(args ...string) (string, string, error) {
-	cmd := exec.Command(binaryPath, args...)
+// runFnorm runs the fnorm CLI with args, re-executing this test binary
+// as the real fnorm process (see TestMain in script_test.go) instead of
+// building and invoking a separate binary. HOME is overridden to home
+// so any journal the run writes (which defaults to a path under HOME)
+// lands in a disposable directory instead of the real one -- every
+// non-dry-run fnorm invocation opens the journal, so callers always
+// pass one, typically t.TempDir().
+func runFnorm(home string, args ...string) (string, string, error) {
+	cmd := exec.Command(os.Args[0], args...)
+	cmd.Env = append(os.Environ(), "GO_FNORM_TEST_MAIN=1", "HOME="+home)
 	var stdout, stderr bytes.Buffer
 	cmd.Stdout = &stdout
 	cmd.Stderr = &stderr
@@ -51,7 +28,7 @@ func runFnorm(args ...string) (string, string, error) {
 
 // TestE2EVersion tests the -version flag
 func TestE2EVersion(t *testing.T) {
-	stdout, _, err := runFnorm("-version")
+	stdout, _, err := runFnorm(t.TempDir(), "-version")
 	if err != nil {
 		t.Fatalf("Failed to run fnorm -version: %v", err)
 	}
@@ -74,7 +51,7 @@ func TestE2EHelp(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			// The help flag causes exit(0) which exec treats as success
-			stdout, stderr, _ := runFnorm(tt.args...)
+			stdout, stderr, _ := runFnorm(t.TempDir(), tt.args...)
 
 			// Help can go to stdout or stderr depending on implementation
 			output := stdout + stderr
@@ -98,7 +75,7 @@ func TestE2EHelp(t *testing.T) {
 
 // TestE2ENoArguments tests behavior when no files are provided
 func TestE2ENoArguments(t *testing.T) {
-	_, stderr, err := runFnorm()
+	_, stderr, err := runFnorm(t.TempDir())
 
 	// Should exit with error
 	if err == nil {
@@ -164,7 +141,7 @@ func TestE2EFileRename(t *testing.T) {
 			}
 
 			// Run fnorm
-			stdout, stderr, err := runFnorm(inputPath)
+			stdout, stderr, err := runFnorm(tempDir, inputPath)
 			if err != nil {
 				t.Fatalf("fnorm failed: %v\nstdout: %s\nstderr: %s", err, stdout, stderr)
 			}
@@ -211,7 +188,7 @@ func TestE2EDryRun(t *testing.T) {
 	}
 
 	// Run fnorm with dry-run
-	stdout, _, err := runFnorm("-dry-run", testPath)
+	stdout, _, err := runFnorm(tempDir, "-dry-run", testPath)
 	if err != nil {
 		t.Fatalf("fnorm dry-run failed: %v", err)
 	}
@@ -256,7 +233,7 @@ func TestE2EMultipleFiles(t *testing.T) {
 	}
 
 	// Run fnorm on all files
-	stdout, _, err := runFnorm(filePaths...)
+	stdout, _, err := runFnorm(tempDir, filePaths...)
 	if err != nil {
 		t.Fatalf("fnorm failed on multiple files: %v", err)
 	}
@@ -282,7 +259,7 @@ func TestE2EMultipleFiles(t *testing.T) {
 
 // TestE2ENonExistentFile tests error handling for non-existent files
 func TestE2ENonExistentFile(t *testing.T) {
-	_, stderr, err := runFnorm("/nonexistent/file.txt")
+	_, stderr, err := runFnorm(t.TempDir(), "/nonexistent/file.txt")
 
 	// Should exit with error code 1
 	if err == nil {
@@ -312,7 +289,7 @@ func TestE2EDirectory(t *testing.T) {
 		t.Fatalf("Failed to create directory: %v", err)
 	}
 
-	_, stderr, err := runFnorm(subDir)
+	_, stderr, err := runFnorm(tempDir, subDir)
 
 	// Should exit with error code 1 for directory
 	if err == nil {
@@ -348,7 +325,7 @@ func TestE2ETargetExists(t *testing.T) {
 		t.Fatalf("Failed to create target file: %v", err)
 	}
 
-	_, stderr, err := runFnorm(sourcePath)
+	_, stderr, err := runFnorm(tempDir, sourcePath)
 
 	// Should exit with error code 1 when target exists
 	if err == nil {
@@ -417,7 +394,7 @@ func TestE2EComplexFilenames(t *testing.T) {
 			}
 
 			// Run fnorm
-			_, stderr, err := runFnorm(inputPath)
+			_, stderr, err := runFnorm(tempDir, inputPath)
 			if err != nil {
 				t.Fatalf("fnorm failed: %v\nstderr: %s", err, stderr)
 			}
@@ -476,7 +453,7 @@ func TestE2ECaseOnlyRename(t *testing.T) {
 			}
 
 			// Run fnorm
-			stdout, stderr, err := runFnorm(inputPath)
+			stdout, stderr, err := runFnorm(tempDir, inputPath)
 			if err != nil {
 				t.Fatalf("fnorm failed: %v\nstdout: %s\nstderr: %s", err, stdout, stderr)
 			}
@@ -559,7 +536,7 @@ func TestE2ECaseAndContentRename(t *testing.T) {
 			}
 
 			// Run fnorm
-			stdout, stderr, err := runFnorm(inputPath)
+			stdout, stderr, err := runFnorm(tempDir, inputPath)
 			if err != nil {
 				t.Fatalf("fnorm failed: %v\nstdout: %s\nstderr: %s", err, stdout, stderr)
 			}
@@ -609,3 +586,300 @@ func TestE2ECaseAndContentRename(t *testing.T) {
 		})
 	}
 }
+
+// TestE2ERecursiveNestedTree tests that -recursive -dirs renames files
+// and directories bottom-up across a nested tree.
+func TestE2ERecursiveNestedTree(t *testing.T) {
+	tempDir := t.TempDir()
+
+	root := filepath.Join(tempDir, "My Project")
+	nested := filepath.Join(root, "Sub Folder")
+	if err := os.MkdirAll(nested, 0755); err != nil {
+		t.Fatalf("Failed to create nested directories: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(nested, "Deep File.txt"), []byte("deep"), 0644); err != nil {
+		t.Fatalf("Failed to create nested file: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "Top File.txt"), []byte("top"), 0644); err != nil {
+		t.Fatalf("Failed to create top-level file: %v", err)
+	}
+
+	stdout, stderr, err := runFnorm(tempDir, "-recursive", "-dirs", root)
+	if err != nil {
+		t.Fatalf("fnorm -recursive -dirs failed: %v\nstdout: %s\nstderr: %s", err, stdout, stderr)
+	}
+
+	expectedFile := filepath.Join(tempDir, "my-project", "sub-folder", "deep-file.txt")
+	if _, err := os.Stat(expectedFile); err != nil {
+		t.Errorf("Expected nested file not found at %s: %v", expectedFile, err)
+	}
+
+	expectedTopFile := filepath.Join(tempDir, "my-project", "top-file.txt")
+	if _, err := os.Stat(expectedTopFile); err != nil {
+		t.Errorf("Expected top-level file not found at %s: %v", expectedTopFile, err)
+	}
+
+	if !strings.Contains(stdout, "Renamed directory:") {
+		t.Errorf("Expected a distinct directory rename message, got: %q", stdout)
+	}
+	if !strings.Contains(stdout, "Renamed file:") {
+		t.Errorf("Expected a distinct file rename message, got: %q", stdout)
+	}
+}
+
+// TestE2ERecursiveWithoutDirsLeavesDirectoriesAlone tests that plain
+// -recursive (without -dirs) walks into directories to rename the files
+// they contain, but leaves the directory names themselves untouched.
+func TestE2ERecursiveWithoutDirsLeavesDirectoriesAlone(t *testing.T) {
+	tempDir := t.TempDir()
+
+	root := filepath.Join(tempDir, "My Project")
+	if err := os.MkdirAll(root, 0755); err != nil {
+		t.Fatalf("Failed to create root: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "Top File.txt"), []byte("top"), 0644); err != nil {
+		t.Fatalf("Failed to create top-level file: %v", err)
+	}
+
+	stdout, stderr, err := runFnorm(tempDir, "-recursive", root)
+	if err != nil {
+		t.Fatalf("fnorm -recursive failed: %v\nstdout: %s\nstderr: %s", err, stdout, stderr)
+	}
+
+	if _, err := os.Stat(root); err != nil {
+		t.Errorf("Expected directory %s to be left in place: %v", root, err)
+	}
+
+	expectedTopFile := filepath.Join(root, "top-file.txt")
+	if _, err := os.Stat(expectedTopFile); err != nil {
+		t.Errorf("Expected top-level file not found at %s: %v", expectedTopFile, err)
+	}
+
+	if strings.Contains(stdout, "Renamed directory:") {
+		t.Errorf("Expected no directory rename message without -dirs, got: %q", stdout)
+	}
+}
+
+// TestE2ERecursiveSkipsSymlinks tests that -recursive does not follow
+// symlinked directories by default.
+func TestE2ERecursiveSkipsSymlinks(t *testing.T) {
+	tempDir := t.TempDir()
+
+	root := filepath.Join(tempDir, "Tree")
+	target := filepath.Join(tempDir, "Target Dir")
+	if err := os.MkdirAll(root, 0755); err != nil {
+		t.Fatalf("Failed to create root: %v", err)
+	}
+	if err := os.MkdirAll(target, 0755); err != nil {
+		t.Fatalf("Failed to create symlink target: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(target, "Linked File.txt"), []byte("linked"), 0644); err != nil {
+		t.Fatalf("Failed to create linked file: %v", err)
+	}
+	if err := os.Symlink(target, filepath.Join(root, "Linked Dir")); err != nil {
+		t.Skipf("symlinks not supported in this environment: %v", err)
+	}
+
+	if _, _, err := runFnorm(tempDir, "-recursive", root); err != nil {
+		t.Fatalf("fnorm -recursive failed: %v", err)
+	}
+
+	if _, err := os.Stat(filepath.Join(target, "linked-file.txt")); err == nil {
+		t.Errorf("Expected symlinked directory contents not to be renamed")
+	}
+}
+
+// TestE2EBatchCollisionSuffix verifies that when three sibling files all
+// normalize to the same target, -on-collision=suffix renames one of them
+// to the plain target and disambiguates the rest with -2, -3, ... suffixes
+// instead of the second and third one failing with "already exists".
+func TestE2EBatchCollisionSuffix(t *testing.T) {
+	tempDir := t.TempDir()
+
+	names := []string{"MY-FILE.TXT", "My File.txt", "my  file.txt"}
+	var paths []string
+	for _, name := range names {
+		p := filepath.Join(tempDir, name)
+		if err := os.WriteFile(p, []byte(name), 0644); err != nil {
+			t.Fatalf("Failed to create %s: %v", name, err)
+		}
+		paths = append(paths, p)
+	}
+
+	args := append([]string{"-on-collision=suffix"}, paths...)
+	stdout, stderr, err := runFnorm(tempDir, args...)
+	if err != nil {
+		t.Fatalf("fnorm -on-collision=suffix failed: %v, stderr: %s", err, stderr)
+	}
+
+	if !strings.Contains(stdout, "1 renamed, 2 suffixed, 0 deduped, 0 skipped, 0 unchanged, 0 failed") {
+		t.Errorf("Expected a batch summary with 1 renamed and 2 suffixed, got stdout: %q", stdout)
+	}
+
+	for _, expected := range []string{"my-file.txt", "my-file-2.txt", "my-file-3.txt"} {
+		if _, err := os.Stat(filepath.Join(tempDir, expected)); err != nil {
+			t.Errorf("Expected %s to exist after disambiguation: %v", expected, err)
+		}
+	}
+}
+
+// TestE2EBatchCollisionFailsByDefault verifies that without -on-collision,
+// the first colliding file wins the plain target and the rest fail with
+// "already exists", matching Apply's existing single-file behavior.
+func TestE2EBatchCollisionFailsByDefault(t *testing.T) {
+	tempDir := t.TempDir()
+
+	names := []string{"MY-FILE.TXT", "My File.txt"}
+	var paths []string
+	for _, name := range names {
+		p := filepath.Join(tempDir, name)
+		if err := os.WriteFile(p, []byte(name), 0644); err != nil {
+			t.Fatalf("Failed to create %s: %v", name, err)
+		}
+		paths = append(paths, p)
+	}
+
+	stdout, stderr, err := runFnorm(tempDir, paths...)
+	if err == nil {
+		t.Fatal("Expected a non-zero exit code when a collision isn't disambiguated")
+	}
+	if !strings.Contains(stderr, "already exists") {
+		t.Errorf("Expected 'already exists' error, got stderr: %q", stderr)
+	}
+	if !strings.Contains(stdout, "0 renamed, 0 suffixed, 0 deduped, 0 skipped, 0 unchanged, 1 failed") &&
+		!strings.Contains(stdout, "1 renamed, 0 suffixed, 0 deduped, 0 skipped, 0 unchanged, 1 failed") {
+		t.Errorf("Expected a batch summary with one failure, got stdout: %q", stdout)
+	}
+}
+
+// TestE2EBatchCollisionDedupe verifies that -on-collision=dedupe removes
+// a colliding file that's byte-identical to whatever already holds its
+// target name, instead of failing or suffixing it.
+func TestE2EBatchCollisionDedupe(t *testing.T) {
+	tempDir := t.TempDir()
+
+	already := filepath.Join(tempDir, "my-file.txt")
+	if err := os.WriteFile(already, []byte("same content"), 0644); err != nil {
+		t.Fatalf("Failed to create %s: %v", already, err)
+	}
+	duplicate := filepath.Join(tempDir, "My File.txt")
+	if err := os.WriteFile(duplicate, []byte("same content"), 0644); err != nil {
+		t.Fatalf("Failed to create %s: %v", duplicate, err)
+	}
+
+	stdout, stderr, err := runFnorm(tempDir, "-on-collision=dedupe", duplicate)
+	if err != nil {
+		t.Fatalf("fnorm -on-collision=dedupe failed: %v, stderr: %s", err, stderr)
+	}
+
+	if !strings.Contains(stdout, "0 renamed, 0 suffixed, 1 deduped, 0 skipped, 0 unchanged, 0 failed") {
+		t.Errorf("Expected a batch summary with 1 deduped, got stdout: %q", stdout)
+	}
+	if _, err := os.Stat(duplicate); !os.IsNotExist(err) {
+		t.Errorf("Expected the duplicate file to be removed, stat err: %v", err)
+	}
+	if _, err := os.Stat(already); err != nil {
+		t.Errorf("Expected the original file to remain: %v", err)
+	}
+}
+
+// TestE2EUndoReversesLastRun tests that fnorm -undo reverses the most
+// recent run's renames using the journal it wrote.
+func TestE2EUndoReversesLastRun(t *testing.T) {
+	home := t.TempDir()
+	tempDir := t.TempDir()
+
+	testFile := "Undo Me.txt"
+	testPath := filepath.Join(tempDir, testFile)
+	if err := os.WriteFile(testPath, []byte("content"), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	stdout, stderr, err := runFnorm(home, testPath)
+	if err != nil {
+		t.Fatalf("fnorm failed: %v\nstdout: %s\nstderr: %s", err, stdout, stderr)
+	}
+	renamedPath := filepath.Join(tempDir, "undo-me.txt")
+	if _, err := os.Stat(renamedPath); err != nil {
+		t.Fatalf("Expected renamed file at %s: %v", renamedPath, err)
+	}
+
+	stdout, stderr, err = runFnorm(home, "-undo")
+	if err != nil {
+		t.Fatalf("fnorm -undo failed: %v\nstdout: %s\nstderr: %s", err, stdout, stderr)
+	}
+	if !strings.Contains(stdout, "Reversed:") {
+		t.Errorf("Expected 'Reversed:' in output, got: %q", stdout)
+	}
+	if _, err := os.Stat(testPath); err != nil {
+		t.Errorf("Expected original file restored at %s: %v", testPath, err)
+	}
+	if _, err := os.Stat(renamedPath); !os.IsNotExist(err) {
+		t.Errorf("Expected renamed file to no longer exist after undo")
+	}
+}
+
+// TestE2EUndoDryRunLeavesFilesAlone tests that fnorm -undo -dry-run
+// previews the reversal without touching the filesystem.
+func TestE2EUndoDryRunLeavesFilesAlone(t *testing.T) {
+	home := t.TempDir()
+	tempDir := t.TempDir()
+
+	testFile := "Preview Undo.txt"
+	testPath := filepath.Join(tempDir, testFile)
+	if err := os.WriteFile(testPath, []byte("content"), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	if _, _, err := runFnorm(home, testPath); err != nil {
+		t.Fatalf("fnorm failed: %v", err)
+	}
+	renamedPath := filepath.Join(tempDir, "preview-undo.txt")
+
+	stdout, stderr, err := runFnorm(home, "-undo", "-dry-run")
+	if err != nil {
+		t.Fatalf("fnorm -undo -dry-run failed: %v\nstdout: %s\nstderr: %s", err, stdout, stderr)
+	}
+	if !strings.Contains(stdout, "Would reverse:") {
+		t.Errorf("Expected 'Would reverse:' in output, got: %q", stdout)
+	}
+	if _, err := os.Stat(renamedPath); err != nil {
+		t.Errorf("Expected renamed file to remain in a dry-run undo: %v", err)
+	}
+}
+
+// TestE2EUndoRefusesModifiedFile tests that fnorm -undo skips an entry
+// whose renamed file was modified since the rename, instead of
+// clobbering it.
+func TestE2EUndoRefusesModifiedFile(t *testing.T) {
+	home := t.TempDir()
+	tempDir := t.TempDir()
+
+	testFile := "Stale Undo.txt"
+	testPath := filepath.Join(tempDir, testFile)
+	if err := os.WriteFile(testPath, []byte("content"), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	if _, _, err := runFnorm(home, testPath); err != nil {
+		t.Fatalf("fnorm failed: %v", err)
+	}
+	renamedPath := filepath.Join(tempDir, "stale-undo.txt")
+	if err := os.WriteFile(renamedPath, []byte("modified after rename"), 0644); err != nil {
+		t.Fatalf("Failed to modify renamed file: %v", err)
+	}
+
+	stdout, stderr, err := runFnorm(home, "-undo")
+	if err != nil {
+		t.Fatalf("fnorm -undo failed: %v\nstdout: %s\nstderr: %s", err, stdout, stderr)
+	}
+	if !strings.Contains(stdout, "modified since") {
+		t.Errorf("Expected a 'modified since' skip message, got: %q", stdout)
+	}
+	if _, err := os.Stat(testPath); !os.IsNotExist(err) {
+		t.Errorf("Expected the original name to remain unused since undo was refused")
+	}
+	if _, err := os.Stat(renamedPath); err != nil {
+		t.Errorf("Expected the modified file to remain at its renamed location: %v", err)
+	}
+}
@@ -2,23 +2,74 @@
 package main
 
 import (
+	"context"
 	"errors"
 	"flag"
 	"fmt"
 	"os"
 	"path/filepath"
-	"strings"
+	"runtime"
+	"time"
 
-	"github.com/laydros/fnorm" //nolint:depguard // allowed internal module import
+	"github.com/spf13/afero"
+
+	"github.com/laydros/fnorm/internal/rename"
 )
 
 var (
 	version     = "dev" // Fallback version, overridden by ldflags from git tags
 	dryRun      = flag.Bool("dry-run", false, "Show what would be renamed without making changes")
 	showVersion = flag.Bool("version", false, "Show version information")
+	recursive   bool
+	include     = flag.String("include", "", "only process files matching this glob pattern")
+	exclude     = flag.String("exclude", "", "skip files matching this glob pattern")
+	skipHidden  = flag.Bool("skip-hidden", false, "skip dotfiles and dot-directories")
+	configPath  = flag.String("config", "", "path to a .fnorm.toml config file (default: discovered upward from the first file given, or from each -recursive root; one profile applies to all files of a run or root)")
+	profileName = flag.String("profile", "", `named profile to use from the config file (default: "default")`)
+	onCollision = flag.String("on-collision", "fail", "how to handle two or more files normalizing to the same name: fail, skip, suffix, or dedupe")
+	maxLength   = flag.Int("max-length", 0, "truncate normalized base names to at most this many bytes, overriding the profile's own max_length (0 keeps the profile's setting)")
+	dirs        = flag.Bool("dirs", false, "with -recursive, also normalize directory names, not just files")
+	jobs        = flag.Int("j", runtime.NumCPU(), "number of renames to run concurrently (1 for serial)")
+	undo        = flag.Bool("undo", false, "reverse the most recent rename journal (pass a journal path as the only argument to use a specific one)")
 	errIsDir    = errors.New("is a directory")
+	osFs        = afero.NewOsFs()
+	runJournal  *rename.Journal
 )
 
+// renamerFor resolves the normalization profile that applies to target
+// (an explicit -config flag, a discovered .fnorm.toml, or the default
+// profile) and returns a Renamer configured to use it, journaling its
+// renames to runJournal if this run opened one.
+func renamerFor(target string) (*rename.Renamer, error) {
+	profile, err := resolveProfile(target)
+	if err != nil {
+		return nil, err
+	}
+	if *maxLength > 0 {
+		profile.MaxLength = *maxLength
+	}
+	renamer := rename.NewWithProfile(osFs, profile)
+	if runJournal != nil {
+		renamer = renamer.WithJournal(runJournal)
+	}
+	return renamer, nil
+}
+
+// openRunJournal opens a fresh journal file for this invocation, so
+// every rename it makes can be reversed later with fnorm -undo.
+func openRunJournal() (*rename.Journal, error) {
+	path, err := rename.DefaultJournalPath(time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("resolve journal path: %w", err)
+	}
+	return rename.OpenJournal(osFs, path)
+}
+
+func init() {
+	flag.BoolVar(&recursive, "recursive", false, "Recursively normalize every file in a tree (add -dirs to include directory names)")
+	flag.BoolVar(&recursive, "r", false, "Shorthand for -recursive")
+}
+
 func main() {
 	flag.Usage = showHelp
 	flag.Parse()
@@ -28,6 +79,14 @@ func main() {
 		return
 	}
 
+	if *undo {
+		if err := runUndo(flag.Args()); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	args := flag.Args()
 	if len(args) == 0 {
 		fmt.Fprintf(os.Stderr, "Error: No files specified\n")
@@ -35,13 +94,29 @@ func main() {
 		os.Exit(1)
 	}
 
+	if !*dryRun {
+		journal, err := openRunJournal()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error opening rename journal: %v\n", err)
+			os.Exit(1)
+		}
+		runJournal = journal
+		defer runJournal.Close()
+	}
+
 	// Track whether any operations failed
 	var hasErrors bool
-	for _, arg := range args {
-		if err := processFile(arg); err != nil {
-			fmt.Fprintf(os.Stderr, "Error processing %s: %v\n", arg, err)
-			hasErrors = true
+	if recursive {
+		for _, arg := range args {
+			if err := processRecursive(arg); err != nil {
+				fmt.Fprintf(os.Stderr, "Error processing %s: %v\n", arg, err)
+				hasErrors = true
+			}
 		}
+	} else if err := processBatch(args); err != nil {
+		// processBatch already reported per-file errors as it went; this
+		// just flags the exit code.
+		hasErrors = true
 	}
 
 	// Exit with appropriate code
@@ -72,9 +147,34 @@ Normalizes file names to safe, consistent format:
   - Leading hyphens trimmed
 
 Flags:
-  -dry-run    Show what would be renamed without making changes
-  -version    Show version information
-  -h, --help  Show this help message
+  -dry-run          Show what would be renamed without making changes
+  -recursive, -r    Recursively normalize every file in a tree
+  -dirs             With -recursive, also normalize directory names
+  -include pattern  Only process files matching this glob pattern
+  -exclude pattern  Skip files matching this glob pattern
+  -skip-hidden      Skip dotfiles and dot-directories
+  -config path      Path to a .fnorm.toml config file (default: discovered
+                    upward from the first file given, or from each
+                    -recursive root, like editorconfig, falling back to
+                    $XDG_CONFIG_HOME/fnorm/config.toml; the profile found
+                    applies to every file of that run or root)
+  -profile name     Named profile to use from the config file (default: "default")
+  -on-collision mode  How to handle two or more files normalizing to the same
+                      name: fail (default), skip, suffix (adds -2, -3, ...),
+                      or dedupe (delete the source if it's byte-identical to
+                      whatever already has that name, else fall back to suffix)
+  -max-length N     Truncate normalized base names to at most N bytes,
+                    overriding the profile's own max_length (0 keeps the
+                    profile's setting)
+  -j N              Number of renames to run concurrently (default: number of
+                    CPUs; use -j 1 for strictly serial processing)
+  -undo [journal]   Reverse the most recent rename journal, in LIFO order
+                    (or a specific one, passed as the only remaining
+                    argument). Refuses to reverse any file that's been
+                    modified since it was renamed. Combine with -dry-run
+                    to preview what would be reversed.
+  -version          Show version information
+  -h, --help        Show this help message
 
 Examples:
   fnorm "My Document.PDF"              # -> my-document.pdf
@@ -83,10 +183,131 @@ Examples:
   fnorm "tcp/udp guide.txt"            # -> tcp-or-udp-guide.txt
   fnorm "CPU Usage 90%%.log"            # -> cpu-usage-90-percent.log
   fnorm -dry-run "File With Spaces.txt"  # Shows preview without changes
+  fnorm -recursive -dirs "My Project"  # Normalize every file and folder in a tree
   fnorm *.jpg                          # Normalize all JPG files
 `)
 }
 
+// parseCollisionPolicy maps the -on-collision flag's string value onto a
+// rename.CollisionPolicy.
+func parseCollisionPolicy(name string) (rename.CollisionPolicy, error) {
+	switch name {
+	case "", "fail":
+		return rename.CollisionFail, nil
+	case "skip":
+		return rename.CollisionSkip, nil
+	case "suffix":
+		return rename.CollisionSuffix, nil
+	case "dedupe":
+		return rename.CollisionDedupe, nil
+	default:
+		return "", fmt.Errorf("unknown -on-collision value %q (want fail, skip, suffix, or dedupe)", name)
+	}
+}
+
+// processBatch normalizes every path in paths together, so two or more
+// paths that normalize to the same target are detected as a collision
+// and disambiguated per -on-collision instead of racing each other
+// through one-file-at-a-time renames. It prints a per-file result line
+// followed by a summary, and reports an error if anything failed.
+func processBatch(paths []string) error {
+	policy, err := parseCollisionPolicy(*onCollision)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return err
+	}
+
+	var hasErrors bool
+	filePaths := make([]string, 0, len(paths))
+	for _, p := range paths {
+		info, err := os.Stat(p)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error processing %s: stat %s: %v\n", p, p, err)
+			hasErrors = true
+			continue
+		}
+		if info.IsDir() {
+			fmt.Fprintf(os.Stderr, "Error processing %s: skipping directory %s: %v\n", p, p, errIsDir)
+			hasErrors = true
+			continue
+		}
+		filePaths = append(filePaths, p)
+	}
+
+	if len(filePaths) == 0 {
+		if hasErrors {
+			return fmt.Errorf("no files to process")
+		}
+		return nil
+	}
+
+	renamer, err := renamerFor(filePaths[0])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return err
+	}
+
+	summary, results := renamer.ApplyBatch(context.Background(), filePaths, rename.Options{DryRun: *dryRun, OnCollision: policy, Workers: *jobs})
+	for _, result := range results {
+		if err := reportBatchResult(result); err != nil {
+			fmt.Fprintf(os.Stderr, "Error processing %s: %v\n", result.From, err)
+			hasErrors = true
+		}
+	}
+
+	fmt.Printf("%d renamed, %d suffixed, %d deduped, %d skipped, %d unchanged, %d failed\n",
+		summary.Renamed, summary.Suffixed, summary.Deduped, summary.Skipped, summary.Unchanged, summary.Failed)
+
+	if hasErrors {
+		return fmt.Errorf("one or more files failed to rename")
+	}
+	return nil
+}
+
+// reportBatchResult prints the outcome of a single ApplyBatch result and
+// returns an error if it failed.
+func reportBatchResult(result rename.Result) error {
+	switch result.Action {
+	case rename.ActionUnchanged:
+		if !*dryRun {
+			fmt.Printf("✓ %s (no changes needed)\n", result.From)
+		}
+		return nil
+	case rename.ActionWouldRename:
+		fmt.Printf("Would rename: %s -> %s\n", result.From, filepath.Base(result.To))
+		return nil
+	case rename.ActionWouldSuffix:
+		fmt.Printf("Would rename (suffixed): %s -> %s\n", result.From, filepath.Base(result.To))
+		return nil
+	case rename.ActionWouldDedupe:
+		fmt.Printf("Would remove as duplicate of: %s -> %s\n", result.From, filepath.Base(result.To))
+		return nil
+	case rename.ActionSkipped:
+		if result.Err == nil {
+			fmt.Printf("Skipped (collision): %s\n", result.From)
+			return nil
+		}
+	}
+
+	if result.Err != nil {
+		if errors.Is(result.Err, os.ErrExist) {
+			return fmt.Errorf("target file already exists %q: %w", filepath.Base(result.To), os.ErrExist)
+		}
+		return fmt.Errorf("failed to rename %q to %q: %w", result.From, result.To, result.Err)
+	}
+
+	switch result.Action {
+	case rename.ActionSuffixed:
+		fmt.Printf("Renamed (suffixed): %s -> %s\n", result.From, filepath.Base(result.To))
+		return nil
+	case rename.ActionDeduped:
+		fmt.Printf("Removed duplicate of: %s -> %s\n", result.From, filepath.Base(result.To))
+		return nil
+	}
+	fmt.Printf("Renamed: %s -> %s\n", result.From, filepath.Base(result.To))
+	return nil
+}
+
 // processFile handles the renaming of a single file, checking for errors
 // and respecting the dry-run flag.
 //
@@ -100,81 +321,265 @@ func processFile(filePath string) error {
 	if err != nil {
 		return fmt.Errorf("stat %s: %w", filePath, err)
 	}
-	if info.IsDir() {
+	if info.IsDir() && !*dirs {
 		return fmt.Errorf("skipping directory %s: %w", filePath, errIsDir)
 	}
 
-	// Split path into directory and filename
-	dir := filepath.Dir(filePath)
 	filename := filepath.Base(filePath)
 
-	normalized := fnorm.Normalize(filename)
+	renamer, err := renamerFor(filePath)
+	if err != nil {
+		return err
+	}
+
+	policy, err := parseCollisionPolicy(*onCollision)
+	if err != nil {
+		return err
+	}
 
-	// If no change is needed
-	if filename == normalized {
+	_, results := renamer.ApplyBatch(context.Background(), []string{filePath}, rename.Options{
+		DryRun:      *dryRun,
+		IncludeDirs: *dirs,
+		OnCollision: policy,
+	})
+	result := results[0]
+
+	switch result.Action {
+	case rename.ActionUnchanged:
 		if !*dryRun {
 			fmt.Printf("✓ %s (no changes needed)\n", filename)
 		}
 		return nil
+	case rename.ActionWouldRename:
+		fmt.Printf("Would rename: %s -> %s\n", filename, filepath.Base(result.To))
+		return nil
+	case rename.ActionWouldSuffix:
+		fmt.Printf("Would rename (suffixed): %s -> %s\n", filename, filepath.Base(result.To))
+		return nil
+	case rename.ActionWouldDedupe:
+		fmt.Printf("Would remove as duplicate of: %s -> %s\n", filename, filepath.Base(result.To))
+		return nil
+	case rename.ActionSkipped:
+		if result.Err == nil {
+			fmt.Printf("Skipped (collision): %s\n", filename)
+			return nil
+		}
 	}
 
-	newPath := filepath.Join(dir, normalized)
+	if result.Err != nil {
+		if errors.Is(result.Err, os.ErrExist) {
+			return fmt.Errorf("target file already exists %q: %w", filepath.Base(result.To), os.ErrExist)
+		}
+		return fmt.Errorf("failed to rename %q to %q: %w", filePath, result.To, result.Err)
+	}
 
-	if *dryRun {
-		fmt.Printf("Would rename: %s -> %s\n", filename, normalized)
+	switch result.Action {
+	case rename.ActionSuffixed:
+		fmt.Printf("Renamed (suffixed): %s -> %s\n", filename, filepath.Base(result.To))
+		return nil
+	case rename.ActionDeduped:
+		fmt.Printf("Removed duplicate of: %s -> %s\n", filename, filepath.Base(result.To))
 		return nil
 	}
+	fmt.Printf("Renamed: %s -> %s\n", filename, filepath.Base(result.To))
+	return nil
+}
 
-	// Check if this is a case-only change
-	if isCaseOnlyChange(filePath, newPath) {
-		// Use two-step rename for case-only changes to work on case-insensitive filesystems
-		if err := performCaseOnlyRename(filePath, newPath); err != nil {
-			return err
-		}
-	} else {
-		// Check if target exists (but only for non-case-only changes)
-		if _, err := os.Stat(newPath); err == nil {
-			return fmt.Errorf("target file already exists %q: %w", normalized, os.ErrExist)
-		}
+// processRecursive walks root and normalizes every file and, with
+// -dirs, every directory it contains, via rename.Renamer.ApplyTree.
+func processRecursive(root string) error {
+	info, err := os.Stat(root)
+	if err != nil {
+		return fmt.Errorf("stat %s: %w", root, err)
+	}
+	if !info.IsDir() {
+		return processFile(root)
+	}
+
+	renamer, err := renamerFor(root)
+	if err != nil {
+		return err
+	}
 
-		if err := os.Rename(filePath, newPath); err != nil {
-			return fmt.Errorf("failed to rename %q to %q: %w", filePath, newPath, err)
+	policy, err := parseCollisionPolicy(*onCollision)
+	if err != nil {
+		return err
+	}
+
+	summary, results, err := renamer.ApplyTree(context.Background(), root, rename.TreeOptions{
+		Options: rename.Options{
+			DryRun:      *dryRun,
+			IncludeDirs: *dirs,
+			OnCollision: policy,
+			Workers:     *jobs,
+		},
+		SkipHidden: *skipHidden,
+		Include:    *include,
+		Exclude:    *exclude,
+	})
+	if err != nil {
+		return fmt.Errorf("walk %s: %w", root, err)
+	}
+
+	var hasErrors bool
+	for _, result := range results {
+		if err := reportTreeResult(result); err != nil {
+			fmt.Fprintf(os.Stderr, "Error processing %s: %v\n", result.From, err)
+			hasErrors = true
 		}
 	}
 
-	fmt.Printf("Renamed: %s -> %s\n", filename, normalized)
+	fmt.Printf("%d renamed, %d suffixed, %d deduped, %d skipped, %d unchanged, %d failed\n",
+		summary.Renamed, summary.Suffixed, summary.Deduped, summary.Skipped, summary.Unchanged, summary.Failed)
+
+	if hasErrors {
+		return fmt.Errorf("one or more entries under %s failed to rename", root)
+	}
 	return nil
 }
 
-// isCaseOnlyChange returns true if the old and new paths differ only in case.
-// This helps detect when a rename is just changing case on case-insensitive filesystems.
-func isCaseOnlyChange(oldPath, newPath string) bool {
-	return strings.EqualFold(oldPath, newPath) && oldPath != newPath
-}
+// reportTreeResult prints the outcome of a single ApplyTree result,
+// reporting directory renames distinctly from file renames, and returns
+// an error if it failed.
+func reportTreeResult(result rename.Result) error {
+	label := "file"
+	if result.IsDir {
+		label = "directory"
+	}
 
-// generateTempName creates a temporary filename by appending a suffix to avoid conflicts.
-func generateTempName(originalPath string) string {
-	return originalPath + ".fnorm-tmp"
+	switch result.Action {
+	case rename.ActionUnchanged:
+		if !*dryRun {
+			fmt.Printf("✓ %s (no changes needed)\n", result.From)
+		}
+		return nil
+	case rename.ActionWouldRename:
+		fmt.Printf("Would rename %s: %s -> %s\n", label, result.From, result.To)
+		return nil
+	case rename.ActionWouldSuffix:
+		fmt.Printf("Would rename %s (suffixed): %s -> %s\n", label, result.From, result.To)
+		return nil
+	case rename.ActionWouldDedupe:
+		fmt.Printf("Would remove %s as duplicate of: %s -> %s\n", label, result.From, result.To)
+		return nil
+	case rename.ActionSkipped:
+		if result.Err == nil {
+			return nil
+		}
+	}
+
+	if result.Err != nil {
+		if errors.Is(result.Err, os.ErrExist) {
+			return fmt.Errorf("target %s already exists %q: %w", label, result.To, os.ErrExist)
+		}
+		return fmt.Errorf("failed to rename %q to %q: %w", result.From, result.To, result.Err)
+	}
+
+	switch result.Action {
+	case rename.ActionSuffixed:
+		fmt.Printf("Renamed %s (suffixed): %s -> %s\n", label, result.From, result.To)
+		return nil
+	case rename.ActionDeduped:
+		fmt.Printf("Removed %s duplicate of: %s -> %s\n", label, result.From, result.To)
+		return nil
+	}
+	fmt.Printf("Renamed %s: %s -> %s\n", label, result.From, result.To)
+	return nil
 }
 
-// performCaseOnlyRename handles renaming files when only the case changes.
-// This uses a two-step process to work around case-insensitive filesystem limitations.
-func performCaseOnlyRename(oldPath, newPath string) error {
-	tempPath := generateTempName(oldPath)
+// runUndo implements fnorm -undo. With no arguments it reverses the
+// newest journal under rename.DefaultJournalDir; with one argument it
+// reverses that journal instead.
+func runUndo(args []string) error {
+	var journalPath string
+	switch len(args) {
+	case 0:
+		path, err := newestJournal()
+		if err != nil {
+			return err
+		}
+		journalPath = path
+	case 1:
+		journalPath = args[0]
+	default:
+		return fmt.Errorf("-undo takes at most one argument (a journal path), got %d", len(args))
+	}
 
-	// Step 1: Rename to temporary file
-	if err := os.Rename(oldPath, tempPath); err != nil {
-		return fmt.Errorf("failed to rename to temporary file %q: %w", tempPath, err)
+	entries, err := rename.ReadJournal(osFs, journalPath)
+	if err != nil {
+		return fmt.Errorf("read journal %s: %w", journalPath, err)
 	}
+	if len(entries) == 0 {
+		fmt.Printf("Journal %s is empty; nothing to undo\n", journalPath)
+		return nil
+	}
+
+	summary, results := rename.New(osFs).Undo(context.Background(), entries, rename.UndoOptions{DryRun: *dryRun})
 
-	// Step 2: Rename from temporary to final name
-	if err := os.Rename(tempPath, newPath); err != nil {
-		// Try to restore original file if second step fails
-		if restoreErr := os.Rename(tempPath, oldPath); restoreErr != nil {
-			return fmt.Errorf("failed to rename to %q and failed to restore original: %v (restore error: %v)", newPath, err, restoreErr)
+	var hasErrors bool
+	for _, result := range results {
+		if err := reportUndoResult(result); err != nil {
+			fmt.Fprintf(os.Stderr, "Error undoing %s: %v\n", result.Entry.To, err)
+			hasErrors = true
 		}
-		return fmt.Errorf("failed to rename %q to %q: %w", tempPath, newPath, err)
 	}
 
+	fmt.Printf("%d reversed, %d skipped, %d failed\n", summary.Reversed, summary.Skipped, summary.Failed)
+
+	if hasErrors {
+		return fmt.Errorf("one or more undo operations failed")
+	}
+	return nil
+}
+
+// reportUndoResult prints the outcome of reversing a single journal
+// entry and returns an error if it failed.
+func reportUndoResult(result rename.UndoResult) error {
+	switch result.Action {
+	case rename.UndoActionStale:
+		fmt.Printf("Skipped (modified since renamed): %s\n", result.Entry.To)
+		return nil
+	case rename.UndoActionWrongDir:
+		fmt.Printf("Skipped (run -undo from %s): %s\n", result.Entry.Cwd, result.Entry.To)
+		return nil
+	case rename.UndoActionWouldReverse:
+		fmt.Printf("Would reverse: %s -> %s\n", result.Entry.To, result.Entry.From)
+		return nil
+	}
+
+	if result.Err != nil {
+		return fmt.Errorf("failed to reverse %q to %q: %w", result.Entry.To, result.Entry.From, result.Err)
+	}
+
+	fmt.Printf("Reversed: %s -> %s\n", result.Entry.To, result.Entry.From)
 	return nil
 }
+
+// newestJournal returns the most recently created journal file under
+// rename.DefaultJournalDir, for fnorm -undo with no path given. Journal
+// file names sort lexically by timestamp, so the lexically greatest
+// name is also the newest.
+func newestJournal() (string, error) {
+	dir, err := rename.DefaultJournalDir()
+	if err != nil {
+		return "", err
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return "", fmt.Errorf("no journals found in %s: %w", dir, err)
+	}
+
+	var newest string
+	for _, e := range entries {
+		if e.IsDir() || filepath.Ext(e.Name()) != ".jsonl" {
+			continue
+		}
+		if e.Name() > filepath.Base(newest) {
+			newest = filepath.Join(dir, e.Name())
+		}
+	}
+	if newest == "" {
+		return "", fmt.Errorf("no journals found in %s", dir)
+	}
+	return newest, nil
+}
@@ -0,0 +1,253 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+// TestMain re-execs this test binary as the real fnorm CLI whenever
+// GO_FNORM_TEST_MAIN is set, instead of calling testing's usual m.Run().
+// runFnorm (integration_test.go) and runFnormCommand (below) both spawn
+// subprocesses this way, so every black-box test in this package drives
+// a real fnorm process without building a separate binary first.
+func TestMain(m *testing.M) {
+	if os.Getenv("GO_FNORM_TEST_MAIN") == "1" {
+		main()
+		return
+	}
+	os.Exit(m.Run())
+}
+
+// txtarFile is one "-- name --" section of a script file.
+type txtarFile struct {
+	name string
+	data []byte
+}
+
+// parseTxtar splits data into its leading comment (everything before
+// the first "-- name --" marker, here used as the script's list of
+// commands) and the file sections that follow. It implements just
+// enough of the txtar format for this package's scripts.
+func parseTxtar(data []byte) ([]byte, []txtarFile) {
+	const marker = "-- "
+	const markerEnd = " --"
+
+	var comment bytes.Buffer
+	var files []txtarFile
+	var current *txtarFile
+
+	for _, line := range bytes.SplitAfter(data, []byte("\n")) {
+		trimmed := strings.TrimRight(string(line), "\n")
+		if strings.HasPrefix(trimmed, marker) && strings.HasSuffix(trimmed, markerEnd) {
+			name := strings.TrimSpace(trimmed[len(marker) : len(trimmed)-len(markerEnd)])
+			files = append(files, txtarFile{name: name})
+			current = &files[len(files)-1]
+			continue
+		}
+		if current == nil {
+			comment.Write(line)
+		} else {
+			current.data = append(current.data, line...)
+		}
+	}
+	return comment.Bytes(), files
+}
+
+// scriptState tracks the outcome of the most recently run fnorm command
+// in a script, so stdout/stderr assertions can check it.
+type scriptState struct {
+	dir    string
+	stdout string
+	stderr string
+}
+
+// TestScripts runs every testdata/script/*.txt file as an independent
+// test case: its txtar file sections materialize the starting
+// filesystem into a fresh temp dir, then its preamble of fnorm
+// invocations and exists/stdout/stderr assertions runs against it. This
+// covers case-only renames, collision modes, and recursive walks with
+// readable scripts instead of hand-mutating package flag vars.
+func TestScripts(t *testing.T) {
+	paths, err := filepath.Glob("testdata/script/*.txt")
+	if err != nil {
+		t.Fatalf("glob testdata/script: %v", err)
+	}
+	if len(paths) == 0 {
+		t.Fatal("no script test cases found under testdata/script")
+	}
+
+	for _, path := range paths {
+		t.Run(strings.TrimSuffix(filepath.Base(path), ".txt"), func(t *testing.T) {
+			runScript(t, path)
+		})
+	}
+}
+
+// runScript parses and executes a single txtar script.
+func runScript(t *testing.T, path string) {
+	data, err := os.ReadFile(path) // #nosec G304 -- test script path
+	if err != nil {
+		t.Fatalf("read %s: %v", path, err)
+	}
+	comment, files := parseTxtar(data)
+
+	state := &scriptState{dir: t.TempDir()}
+	for _, f := range files {
+		target := filepath.Join(state.dir, f.name)
+		if err := os.MkdirAll(filepath.Dir(target), 0o750); err != nil {
+			t.Fatalf("create directory for %s: %v", f.name, err)
+		}
+		if err := os.WriteFile(target, f.data, 0o644); err != nil {
+			t.Fatalf("write %s: %v", f.name, err)
+		}
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(comment))
+	for lineNum := 1; scanner.Scan(); lineNum++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if err := runScriptLine(state, line); err != nil {
+			t.Fatalf("%s:%d: %s: %v", path, lineNum, line, err)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		t.Fatalf("read %s: %v", path, err)
+	}
+}
+
+// runScriptLine executes a single preamble line against state.
+func runScriptLine(state *scriptState, line string) error {
+	negate := false
+	if strings.HasPrefix(line, "!") {
+		negate = true
+		line = strings.TrimSpace(strings.TrimPrefix(line, "!"))
+	}
+
+	words, err := splitWords(line)
+	if err != nil {
+		return err
+	}
+	if len(words) == 0 {
+		return fmt.Errorf("empty command")
+	}
+
+	switch words[0] {
+	case "fnorm":
+		if negate {
+			return fmt.Errorf("fnorm cannot be negated with !")
+		}
+		return runFnormCommand(state, words[1:])
+	case "exists":
+		if len(words) != 2 {
+			return fmt.Errorf("exists takes exactly one path")
+		}
+		_, statErr := os.Stat(filepath.Join(state.dir, words[1]))
+		found := statErr == nil
+		if found == negate {
+			if negate {
+				return fmt.Errorf("expected %s not to exist", words[1])
+			}
+			return fmt.Errorf("expected %s to exist: %v", words[1], statErr)
+		}
+		return nil
+	case "stdout":
+		return matchOutput("stdout", state.stdout, words[1:], negate)
+	case "stderr":
+		return matchOutput("stderr", state.stderr, words[1:], negate)
+	default:
+		return fmt.Errorf("unknown command %q", words[0])
+	}
+}
+
+// runFnormCommand runs the fnorm binary (via TestMain's subprocess
+// re-exec) with args in state.dir, recording its stdout and stderr for
+// later stdout/stderr assertions. HOME is overridden to state.dir so a
+// non-dry-run command's journal write lands in the script's disposable
+// temp dir instead of the real one under ~/.local/state/fnorm.
+func runFnormCommand(state *scriptState, args []string) error {
+	cmd := exec.Command(os.Args[0], args...)
+	cmd.Dir = state.dir
+	cmd.Env = append(os.Environ(), "GO_FNORM_TEST_MAIN=1", "HOME="+state.dir)
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	_ = cmd.Run() // a non-zero exit is often the scenario under test
+
+	state.stdout = stdout.String()
+	state.stderr = stderr.String()
+	return nil
+}
+
+// matchOutput checks output against every one of patterns (each a
+// regexp), honoring a leading "!" negation. A bare "." pattern (as in
+// `stderr .`) matches any non-empty output.
+func matchOutput(name, output string, patterns []string, negate bool) error {
+	if len(patterns) == 0 {
+		return fmt.Errorf("%s requires a pattern", name)
+	}
+	for _, pattern := range patterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return fmt.Errorf("%s: bad pattern %q: %w", name, pattern, err)
+		}
+		matched := re.MatchString(output)
+		if matched == negate {
+			if negate {
+				return fmt.Errorf("%s unexpectedly matched %q: %q", name, pattern, output)
+			}
+			return fmt.Errorf("%s did not match %q: %q", name, pattern, output)
+		}
+	}
+	return nil
+}
+
+// splitWords splits line into shell-like words, honoring single- and
+// double-quoted substrings so arguments like 'My File.txt' stay intact.
+func splitWords(line string) ([]string, error) {
+	var words []string
+	var current strings.Builder
+	var inWord bool
+	var quote rune
+
+	flush := func() {
+		if inWord {
+			words = append(words, current.String())
+			current.Reset()
+			inWord = false
+		}
+	}
+
+	for _, r := range line {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			} else {
+				current.WriteRune(r)
+			}
+		case r == '\'' || r == '"':
+			quote = r
+			inWord = true
+		case r == ' ' || r == '\t':
+			flush()
+		default:
+			inWord = true
+			current.WriteRune(r)
+		}
+	}
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated quote in %q", line)
+	}
+	flush()
+	return words, nil
+}
@@ -5,24 +5,120 @@ import (
 	"path/filepath"
 	"regexp"
 	"strings"
-)
+	"unicode"
 
-const (
-	spaceReplacer         = "-"
-	forbiddenCharsPattern = `[^a-z0-9\-_.]`
+	"golang.org/x/text/runes"
+	"golang.org/x/text/unicode/norm"
 )
 
+const spaceReplacer = "-"
+
 var (
-	forbiddenCharsRe    = regexp.MustCompile(forbiddenCharsPattern)
-	multiHyphenRe       = regexp.MustCompile(`-+`)
 	specialReplacements = map[string]string{
 		"/": "-or-",
 		"&": "-and-",
 		"@": "-at-",
 		"%": "-percent",
 	}
+
+	// nonASCIIRe matches anything left over after folding that still isn't
+	// a plain ASCII slug character.
+	nonASCIIRe = regexp.MustCompile(`[^\x00-\x7F]`)
+
+	// nonSpacingMarks strips combining diacritics produced by NFKD
+	// decomposition (e.g. the combining acute accent in "é" -> "e´").
+	nonSpacingMarks = runes.Remove(runes.In(unicode.Mn))
+)
+
+// Mode selects how Normalize handles runes outside the ASCII slug charset.
+type Mode int
+
+const (
+	// ModeASCIIFold decomposes accented Latin letters to their ASCII base
+	// (NFKD + drop combining marks) and replaces anything still non-ASCII
+	// with a hyphen. This is the historical behavior of Normalize.
+	ModeASCIIFold Mode = iota
+	// ModePreserveScript leaves letters from non-Latin scripts (Cyrillic,
+	// Hangul, Devanagari, ...) intact instead of folding or dropping them,
+	// while still lowercasing and collapsing hyphens around them.
+	ModePreserveScript
+	// ModeStripNonASCII folds like ModeASCIIFold, but drops anything
+	// outside [a-z0-9._-] instead of collapsing it to a hyphen.
+	ModeStripNonASCII
 )
 
+// Options controls how NormalizeWith transforms a filename.
+type Options struct {
+	// Transliterate selects how non-ASCII runes are handled. The zero
+	// value is ModeASCIIFold, matching the original Normalize behavior.
+	Transliterate Mode
+}
+
+// preservedScripts lists the non-Latin scripts ModePreserveScript keeps
+// intact rather than folding to ASCII.
+var preservedScripts = []*unicode.RangeTable{
+	unicode.Cyrillic,
+	unicode.Hangul,
+	unicode.Devanagari,
+	unicode.Han,
+	unicode.Hiragana,
+	unicode.Katakana,
+	unicode.Greek,
+	unicode.Arabic,
+	unicode.Hebrew,
+}
+
+// Profile declares the normalization rules Normalize should apply, so
+// callers can encode team-specific conventions (underscores instead of
+// hyphens, preserved uppercase, a looser punctuation policy, ...) instead
+// of being stuck with the historical hyphen-and-lowercase defaults.
+type Profile struct {
+	// Separator replaces spaces and collapses runs of forbidden
+	// characters. Defaults to "-" if left empty.
+	Separator string
+	// LowerCase lowercases the result when true.
+	LowerCase bool
+	// AllowedChars is the body of a regex character class (e.g.
+	// `a-z0-9\-_.`) naming the characters that survive normalization
+	// unchanged; everything else collapses to Separator. Defaults to
+	// `a-z0-9\-_.` if left empty.
+	AllowedChars string
+	// SpecialReplacements maps literal substrings (e.g. "/", "&") to their
+	// replacement before transliteration runs.
+	SpecialReplacements map[string]string
+	// MaxLength truncates the base name (not the extension) to at most
+	// this many bytes if positive. Zero means no limit.
+	MaxLength int
+	// Transliterate selects how non-ASCII runes are handled.
+	Transliterate Mode
+	// PreserveExtensionCase leaves the file extension's case untouched
+	// instead of lowercasing it.
+	PreserveExtensionCase bool
+	// TrimChars is the cutset of characters trimmed from both ends of the
+	// base name before any other transformation runs. Defaults to
+	// stripping whitespace and leading/trailing dots if left empty.
+	TrimChars string
+}
+
+// DefaultProfile returns the historical Normalize behavior: hyphen
+// separator, lowercase, the original forbidden-character set and special
+// replacements, no length limit, and ASCII folding.
+func DefaultProfile() Profile {
+	replacements := make(map[string]string, len(specialReplacements))
+	for orig, repl := range specialReplacements {
+		replacements[orig] = repl
+	}
+
+	return Profile{
+		Separator:             spaceReplacer,
+		LowerCase:             true,
+		AllowedChars:          `a-z0-9\-_.`,
+		SpecialReplacements:   replacements,
+		Transliterate:         ModeASCIIFold,
+		PreserveExtensionCase: false,
+	}
+}
+
 // Normalize transforms a filename according to the normalization rules:
 // spaces to hyphens, lowercase conversion, forbidden character replacement, etc.
 //
@@ -30,11 +126,40 @@ var (
 //
 //	normalized := Normalize("My File.PDF")
 //	// normalized == "my-file.pdf"
+//
+// Normalize is a thin wrapper around NormalizeWith using ModeASCIIFold,
+// kept for backward compatibility.
 func Normalize(filename string) string {
+	return NormalizeWith(filename, Options{Transliterate: ModeASCIIFold})
+}
+
+// NormalizeWith transforms a filename like Normalize, but lets the caller
+// choose how non-ASCII runes are folded via opts.Transliterate.
+func NormalizeWith(filename string, opts Options) string {
+	profile := DefaultProfile()
+	profile.Transliterate = opts.Transliterate
+	return NormalizeProfile(filename, profile)
+}
+
+// NormalizeProfile transforms a filename according to p, so callers can
+// swap in a Profile loaded from a config file instead of the historical
+// hyphen-and-lowercase defaults.
+func NormalizeProfile(filename string, p Profile) string {
 	if filename == "" {
 		return ""
 	}
 
+	sep := p.Separator
+	if sep == "" {
+		sep = spaceReplacer
+	}
+	allowedChars := p.AllowedChars
+	if allowedChars == "" {
+		allowedChars = `a-z0-9\-_.`
+	}
+	forbiddenRe := regexp.MustCompile(`[^` + allowedChars + `]`)
+	collapseRe := regexp.MustCompile(regexp.QuoteMeta(sep) + `+`)
+
 	// Get file extension and base name
 	ext := filepath.Ext(filename)
 	nameOnly := strings.TrimSuffix(filename, ext)
@@ -43,65 +168,152 @@ func Normalize(filename string) string {
 	}
 
 	// Trim unwanted characters from the base name
-	nameOnly = strings.TrimSpace(nameOnly)
-	nameOnly = strings.Trim(nameOnly, ".")
+	if p.TrimChars == "" {
+		nameOnly = strings.TrimSpace(nameOnly)
+		nameOnly = strings.Trim(nameOnly, ".")
+	} else {
+		nameOnly = strings.Trim(nameOnly, p.TrimChars)
+	}
 
 	// Apply transformations to name only
 	result := nameOnly
 
-	// 1. Replace spaces with hyphens
-	result = strings.ReplaceAll(result, " ", spaceReplacer)
+	// 1. Replace spaces with the separator
+	result = strings.ReplaceAll(result, " ", sep)
 
 	// 2. Convert to lowercase
-	result = strings.ToLower(result)
+	if p.LowerCase {
+		result = strings.ToLower(result)
+	}
 
 	// 3. Apply special character replacements
-	for orig, repl := range specialReplacements {
+	for orig, repl := range p.SpecialReplacements {
 		result = strings.ReplaceAll(result, orig, repl)
 	}
 
-	// 4. Transliterate accented characters to ASCII
-	result = transliterate(result)
+	// 4. Fold or preserve non-ASCII runes according to the selected mode
+	result = transliterate(result, p.Transliterate)
 
-	// 5. Replace forbidden characters with hyphens
-	result = forbiddenCharsRe.ReplaceAllString(result, "-")
+	// 5. Replace forbidden characters with the separator. ModeStripNonASCII
+	// has already dropped non-ASCII runes and drops the remaining forbidden
+	// characters here too, and ModePreserveScript runes are handled
+	// separately since forbiddenRe only recognizes ASCII.
+	switch p.Transliterate {
+	case ModePreserveScript:
+		result = stripForbiddenPreserving(result, forbiddenRe, sep)
+	case ModeStripNonASCII:
+		result = forbiddenRe.ReplaceAllString(result, "")
+	default:
+		result = forbiddenRe.ReplaceAllString(result, sep)
+	}
+
+	// 6. Clean up multiple consecutive separators
+	result = collapseRe.ReplaceAllString(result, sep)
 
-	// 6. Clean up multiple consecutive hyphens
-	result = multiHyphenRe.ReplaceAllString(result, "-")
+	// 7. Trim leading separators
+	result = strings.TrimLeft(result, sep)
 
-	// 7. Trim leading hyphens
-	result = strings.TrimLeft(result, "-")
+	// 8. Truncate the base name if it exceeds MaxLength, trimming any
+	// separator left dangling by the cut.
+	if p.MaxLength > 0 && len(result) > p.MaxLength {
+		result = strings.TrimRight(result[:p.MaxLength], sep)
+	}
 
-	// Convert extension to lowercase too
-	ext = strings.ToLower(ext)
+	// Convert extension to lowercase too, unless the profile asks to
+	// preserve its case.
+	if !p.PreserveExtensionCase {
+		ext = strings.ToLower(ext)
+	}
 
 	return result + ext
 }
 
-var transliterations = map[rune]string{
-	'á': "a", 'à': "a", 'â': "a", 'ä': "a", 'ã': "a", 'å': "a",
-	'é': "e", 'è': "e", 'ê': "e", 'ë': "e",
-	'í': "i", 'ì': "i", 'î': "i", 'ï': "i",
-	'ó': "o", 'ò': "o", 'ô': "o", 'ö': "o", 'õ': "o",
-	'ú': "u", 'ù': "u", 'û': "u", 'ü': "u",
-	'ñ': "n",
-	'ç': "c",
-	'æ': "ae", 'œ': "oe",
-	'ø': "o", 'ß': "ss",
-	// Typography
-	'–': "-", '—': "-", // en/em dashes
-	'‘': "'", '’': "'", // smart single quotes
-	'“': "\"", '”': "\"", // smart double quotes
+// NormalizePath splits p on filepath.Separator, normalizes each segment
+// independently with opts, and rejoins them, so a path like
+// "My Folder/Résumé Final.PDF" becomes "my-folder/resume-final.pdf".
+func NormalizePath(p string, opts Options) string {
+	segments := strings.Split(p, string(filepath.Separator))
+	for i, seg := range segments {
+		segments[i] = NormalizeWith(seg, opts)
+	}
+	return strings.Join(segments, string(filepath.Separator))
 }
 
-func transliterate(s string) string {
+// transliterate folds non-ASCII runes in s according to mode.
+func transliterate(s string, mode Mode) string {
+	switch mode {
+	case ModePreserveScript:
+		return preserveScript(s)
+	case ModeStripNonASCII:
+		return nonASCIIRe.ReplaceAllString(nonSpacingMarks.String(norm.NFKD.String(s)), "")
+	default:
+		return asciiFold(s)
+	}
+}
+
+// asciiFold decomposes s with NFKD and drops combining marks, leaving any
+// remaining non-ASCII rune as "?" so the forbidden-char pass below turns
+// it into a separator.
+func asciiFold(s string) string {
+	decomposed := norm.NFKD.String(s)
+	decomposed = nonSpacingMarks.String(decomposed)
+
+	var b strings.Builder
+	for _, r := range decomposed {
+		if r > unicode.MaxASCII {
+			b.WriteRune('?')
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// stripForbiddenPreserving replaces characters forbiddenRe rejects with
+// sep, like forbiddenRe.ReplaceAllString, but leaves runes from the
+// preserved scripts alone since forbiddenRe only recognizes ASCII. A
+// script's combining marks (e.g. a Devanagari vowel sign or virama)
+// fall within that script's own range table alongside its letters, so
+// checking script membership alone is enough to keep them intact too.
+func stripForbiddenPreserving(s string, forbiddenRe *regexp.Regexp, sep string) string {
 	var b strings.Builder
 	for _, r := range s {
-		if repl, ok := transliterations[r]; ok {
-			b.WriteString(repl)
-		} else {
+		switch {
+		case r <= unicode.MaxASCII && forbiddenRe.MatchString(string(r)):
+			b.WriteString(sep)
+		case r > unicode.MaxASCII && !unicode.In(r, preservedScripts...):
+			b.WriteString(sep)
+		default:
 			b.WriteRune(r)
 		}
 	}
 	return b.String()
 }
+
+// preserveScript decomposes s with NFKD like asciiFold, but keeps any
+// rune belonging to a preserved script (Cyrillic, Hangul, Devanagari,
+// ...) intact instead of folding or dropping it. A preserved script's
+// combining marks (e.g. a Devanagari vowel sign or virama) fall within
+// that script's own range table, so they're kept right alongside its
+// letters; a combining mark NFKD split off a folded script's letter
+// (e.g. the acute accent in "é") is dropped, same as asciiFold.
+func preserveScript(s string) string {
+	decomposed := norm.NFKD.String(s)
+
+	var b strings.Builder
+	for _, r := range decomposed {
+		switch {
+		case r <= unicode.MaxASCII:
+			b.WriteRune(r)
+		case unicode.In(r, preservedScripts...):
+			b.WriteRune(r)
+		case unicode.In(r, unicode.Mn, unicode.Mc):
+			continue
+		default:
+			b.WriteRune('?')
+		}
+	}
+	// Recompose preserved-script runes (e.g. Hangul jamo back into
+	// syllable blocks); ASCII and "?" are unaffected by NFC.
+	return norm.NFC.String(b.String())
+}
@@ -0,0 +1,205 @@
+package normalize
+
+import "testing"
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name:     "spaces replaced with hyphens",
+			input:    "My File.txt",
+			expected: "my-file.txt",
+		},
+		{
+			name:     "unicode characters transliterated",
+			input:    "café.txt",
+			expected: "cafe.txt",
+		},
+		{
+			name:     "typographic dashes transliterated",
+			input:    "foo–bar—baz.txt",
+			expected: "foo-bar-baz.txt",
+		},
+		{
+			name:     "curly apostrophes transliterated",
+			input:    "rock’n’roll.txt",
+			expected: "rock-n-roll.txt",
+		},
+		{
+			name:     "vietnamese diacritics folded",
+			input:    "Tiếng Việt.txt",
+			expected: "tieng-viet.txt",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got := Normalize(tc.input)
+			if got != tc.expected {
+				t.Fatalf("expected %q, got %q", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestNormalizeWithModeASCIIFold(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name:     "cyrillic folds to nothing but the extension",
+			input:    "Привет.txt",
+			expected: ".txt",
+		},
+		{
+			name:     "korean folds to nothing but the extension",
+			input:    "안녕하세요.txt",
+			expected: ".txt",
+		},
+		{
+			name:     "sanskrit devanagari folds to nothing but the extension",
+			input:    "नमस्ते.txt",
+			expected: ".txt",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got := NormalizeWith(tc.input, Options{Transliterate: ModeASCIIFold})
+			if got != tc.expected {
+				t.Fatalf("expected %q, got %q", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestNormalizeWithModePreserveScript(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name:     "cyrillic preserved",
+			input:    "Привет Мир.txt",
+			expected: "привет-мир.txt",
+		},
+		{
+			name:     "korean preserved",
+			input:    "안녕하세요 세계.txt",
+			expected: "안녕하세요-세계.txt",
+		},
+		{
+			name:     "sanskrit devanagari preserved",
+			input:    "नमस्ते दुनिया.txt",
+			expected: "नमस्ते-दुनिया.txt",
+		},
+		{
+			name:     "latin diacritics still fold",
+			input:    "café.txt",
+			expected: "cafe.txt",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got := NormalizeWith(tc.input, Options{Transliterate: ModePreserveScript})
+			if got != tc.expected {
+				t.Fatalf("expected %q, got %q", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestNormalizeWithModeStripNonASCII(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name:     "cyrillic dropped entirely",
+			input:    "Привет.txt",
+			expected: ".txt",
+		},
+		{
+			name:     "latin diacritics still fold",
+			input:    "café.txt",
+			expected: "cafe.txt",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got := NormalizeWith(tc.input, Options{Transliterate: ModeStripNonASCII})
+			if got != tc.expected {
+				t.Fatalf("expected %q, got %q", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestNormalizeProfileTrimChars(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		trimChars string
+		expected  string
+	}{
+		{
+			name:     "empty TrimChars keeps the default whitespace and dot trim",
+			input:    " ..File.. .txt",
+			expected: "file.txt",
+		},
+		{
+			name:      "custom TrimChars trims underscores instead",
+			input:     "__File__.txt",
+			trimChars: "_",
+			expected:  "file.txt",
+		},
+	}
+
+	for _, tc := range tests {
+		profile := DefaultProfile()
+		profile.TrimChars = tc.trimChars
+		t.Run(tc.name, func(t *testing.T) {
+			got := NormalizeProfile(tc.input, profile)
+			if got != tc.expected {
+				t.Fatalf("expected %q, got %q", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestNormalizePath(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name:     "segments normalized independently",
+			input:    "My Folder/Résumé Final.PDF",
+			expected: "my-folder/resume-final.pdf",
+		},
+		{
+			name:     "single segment matches Normalize",
+			input:    "My File.txt",
+			expected: "my-file.txt",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got := NormalizePath(tc.input, Options{Transliterate: ModeASCIIFold})
+			if got != tc.expected {
+				t.Fatalf("expected %q, got %q", tc.expected, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,417 @@
+package rename
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	"github.com/spf13/afero"
+)
+
+// CollisionPolicy selects how ApplyBatch disambiguates two or more paths
+// that normalize to the same target name.
+type CollisionPolicy string
+
+const (
+	// CollisionFail reports os.ErrExist for every colliding path, same as
+	// Apply does for a single conflicting rename. This is the default.
+	CollisionFail CollisionPolicy = "fail"
+	// CollisionSkip leaves colliding paths untouched, reporting
+	// ActionSkipped for them.
+	CollisionSkip CollisionPolicy = "skip"
+	// CollisionSuffix renames colliding paths to target-2, target-3, ...
+	// (inserted before the extension) until a unique name is found.
+	CollisionSuffix CollisionPolicy = "suffix"
+	// CollisionDedupe compares a colliding path's content digest (see
+	// ContentDigest) against whatever already occupies its target. An
+	// exact match is treated as a duplicate and the colliding path is
+	// removed instead of renamed; a mismatch falls back to
+	// CollisionSuffix. Only a target that already exists on disk before
+	// the batch can match: every rename in the batch is planned before
+	// any of them runs, so a target that another path in the same batch
+	// is about to take is not compared against, in dry runs and real
+	// runs alike.
+	CollisionDedupe CollisionPolicy = "dedupe"
+)
+
+const maxSuffixAttempts = 10000
+
+// ActionSuffixed means the path was renamed to a disambiguated,
+// suffixed name because its plain target collided with another path.
+const ActionSuffixed Action = "suffixed"
+
+// ActionWouldSuffix is ActionSuffixed's Options.DryRun counterpart.
+const ActionWouldSuffix Action = "would-suffix"
+
+// ActionDeduped means the path was removed instead of renamed because
+// CollisionDedupe found its content identical to whatever already
+// occupied its target.
+const ActionDeduped Action = "deduped"
+
+// ActionWouldDedupe is ActionDeduped's Options.DryRun counterpart.
+const ActionWouldDedupe Action = "would-dedupe"
+
+// Summary tallies the outcomes of an ApplyBatch call.
+type Summary struct {
+	Renamed   int
+	Suffixed  int
+	Deduped   int
+	Skipped   int
+	Unchanged int
+	Failed    int
+}
+
+// ApplyBatch normalizes every path in paths like Apply, but first
+// computes all of their target names together so it can detect two or
+// more paths landing on the same target before touching the filesystem.
+// Collisions (including a target that collides with a pre-existing file
+// not in paths) are resolved per opts.OnCollision. Once every rename has
+// been planned and found collision-free, the actual filesystem mutations
+// run across opts.Workers goroutines (serially if Workers <= 1); the
+// returned Results are always in the same order as paths regardless.
+func (r *Renamer) ApplyBatch(ctx context.Context, paths []string, opts Options) (Summary, []Result) {
+	results := make(map[string]*Result, len(paths))
+	order := make([]string, 0, len(paths))
+	isDirByPath := make(map[string]bool, len(paths))
+
+	targetsByDir := map[string]map[string][]string{}
+	for _, p := range paths {
+		if err := ctx.Err(); err != nil {
+			results[p] = &Result{From: p, Err: err}
+			order = append(order, p)
+			continue
+		}
+
+		info, err := r.fs.Stat(p)
+		if err != nil {
+			results[p] = &Result{From: p, Err: err}
+			order = append(order, p)
+			continue
+		}
+		isDirByPath[p] = info.IsDir()
+		if info.IsDir() && !opts.IncludeDirs {
+			results[p] = &Result{From: p, Action: ActionSkipped}
+			order = append(order, p)
+			continue
+		}
+
+		order = append(order, p)
+		dir := filepath.Dir(p)
+		target := r.normalize(filepath.Base(p))
+		if targetsByDir[dir] == nil {
+			targetsByDir[dir] = map[string][]string{}
+		}
+		targetsByDir[dir][target] = append(targetsByDir[dir][target], p)
+	}
+
+	var ops []execOp
+	for dir, targets := range targetsByDir {
+		r.resolveDir(dir, targets, opts, results, &ops)
+	}
+	for path, res := range r.runOps(ctx, ops, opts.Workers) {
+		results[path] = res
+	}
+
+	summary := Summary{}
+	final := make([]Result, 0, len(order))
+	for _, p := range order {
+		res := results[p]
+		res.IsDir = isDirByPath[p]
+		final = append(final, *res)
+		switch {
+		case res.Err != nil:
+			summary.Failed++
+		case res.Action == ActionRenamed || res.Action == ActionWouldRename:
+			summary.Renamed++
+		case res.Action == ActionSuffixed || res.Action == ActionWouldSuffix:
+			summary.Suffixed++
+		case res.Action == ActionDeduped || res.Action == ActionWouldDedupe:
+			summary.Deduped++
+		case res.Action == ActionSkipped:
+			summary.Skipped++
+		case res.Action == ActionUnchanged:
+			summary.Unchanged++
+		}
+	}
+	return summary, final
+}
+
+// resolveDir assigns a Result to every source path in targets, a dir's
+// worth of filepath.Base(path) -> normalized target mapping, detecting
+// and disambiguating collisions per opts.OnCollision. This planning pass
+// is purely computational (directory listings aside) and always runs
+// serially, since later decisions depend on earlier ones; the actual
+// renames it plans are appended to ops and executed afterward, possibly
+// in parallel. A nil entry in results means "see ops": the real Result
+// lands there once ops has run.
+func (r *Renamer) resolveDir(dir string, targets map[string][]string, opts Options, results map[string]*Result, ops *[]execOp) {
+	occupied := map[string]bool{}
+	for _, name := range r.existingNames(dir, targets) {
+		occupied[name] = true
+	}
+
+	sortedTargets := make([]string, 0, len(targets))
+	for target := range targets {
+		sortedTargets = append(sortedTargets, target)
+	}
+	sort.Strings(sortedTargets)
+
+	for _, target := range sortedTargets {
+		sources := append([]string(nil), targets[target]...)
+		sort.Strings(sources)
+
+		winner := -1
+		for i, src := range sources {
+			if filepath.Base(src) == target {
+				winner = i
+				break
+			}
+		}
+		if winner == -1 && !occupied[target] {
+			winner = 0
+		}
+
+		if winner != -1 {
+			src := sources[winner]
+			res := r.resolveSingle(dir, src, target, opts, ops)
+			results[src] = res
+			// A nil res means the rename was deferred to ops; assume it
+			// will succeed so later targets in this directory don't
+			// needlessly collide-avoid something that's already taken.
+			if res == nil || res.Err == nil {
+				occupied[target] = true
+			}
+			sources = append(sources[:winner], sources[winner+1:]...)
+		}
+
+		for _, src := range sources {
+			results[src] = r.resolveCollision(dir, src, target, opts, occupied, ops)
+		}
+	}
+}
+
+// existingNames lists the base names already on disk in dir, excluding
+// the sources themselves (they're about to move and shouldn't count as
+// pre-occupying their own target).
+func (r *Renamer) existingNames(dir string, targets map[string][]string) []string {
+	sourceBase := map[string]bool{}
+	for _, sources := range targets {
+		for _, src := range sources {
+			sourceBase[filepath.Base(src)] = true
+		}
+	}
+
+	entries, err := afero.ReadDir(r.fs, dir)
+	if err != nil {
+		return nil
+	}
+
+	names := make([]string, 0, len(entries))
+	for _, e := range entries {
+		if !sourceBase[e.Name()] {
+			names = append(names, e.Name())
+		}
+	}
+	return names
+}
+
+// resolveSingle plans the rename of src to target, the uncontested
+// winner of its collision group (or its only candidate). A nil return
+// means the rename itself was appended to ops to run later.
+func (r *Renamer) resolveSingle(dir, src, target string, opts Options, ops *[]execOp) *Result {
+	filename := filepath.Base(src)
+	if filename == target {
+		return &Result{From: src, To: src, Action: ActionUnchanged}
+	}
+
+	to := filepath.Join(dir, target)
+	if opts.DryRun {
+		return &Result{From: src, To: to, Action: ActionWouldRename}
+	}
+
+	*ops = append(*ops, execOp{path: src, kind: opRename, from: src, to: to, action: ActionRenamed})
+	return nil
+}
+
+// resolveCollision plans how to disambiguate src, whose normalized name
+// collided with another path (or a pre-existing file), per
+// opts.OnCollision. A nil return means the fix-up itself was appended to
+// ops to run later.
+func (r *Renamer) resolveCollision(dir, src, target string, opts Options, occupied map[string]bool, ops *[]execOp) *Result {
+	to := filepath.Join(dir, target)
+
+	switch opts.OnCollision {
+	case CollisionSkip:
+		return &Result{From: src, To: to, Action: ActionSkipped}
+
+	case CollisionDedupe:
+		same, err := r.sameContent(src, to)
+		if err != nil {
+			return &Result{From: src, To: to, Err: err}
+		}
+		if !same {
+			fallback := opts
+			fallback.OnCollision = CollisionSuffix
+			return r.resolveCollision(dir, src, target, fallback, occupied, ops)
+		}
+		if opts.DryRun {
+			return &Result{From: src, To: to, Action: ActionWouldDedupe}
+		}
+		*ops = append(*ops, execOp{path: src, kind: opRemove, from: src, to: to, action: ActionDeduped})
+		return nil
+
+	case CollisionSuffix:
+		name, err := suffixedName(target, occupied)
+		if err != nil {
+			return &Result{From: src, To: to, Err: err}
+		}
+		occupied[name] = true
+		suffixedTo := filepath.Join(dir, name)
+
+		if opts.DryRun {
+			return &Result{From: src, To: suffixedTo, Action: ActionWouldSuffix}
+		}
+		*ops = append(*ops, execOp{path: src, kind: opRename, from: src, to: suffixedTo, action: ActionSuffixed})
+		return nil
+
+	default: // CollisionFail, or unset
+		return &Result{From: src, To: to, Err: os.ErrExist}
+	}
+}
+
+// opKind selects the filesystem mutation an execOp performs.
+type opKind int
+
+const (
+	opRename opKind = iota
+	opRemove
+)
+
+// execOp is a single planned filesystem mutation, deferred out of the
+// (necessarily serial) planning pass so independent ops can run
+// concurrently across a worker pool.
+type execOp struct {
+	path   string // key to store the finished Result under (== from)
+	kind   opKind
+	from   string
+	to     string
+	action Action // Result.Action to report on success
+}
+
+// runOps executes every op, in parallel across workers goroutines when
+// workers > 1, or serially (preserving ops' order) otherwise. Every op
+// gets a Result; once ctx is canceled, ops not yet dispatched are
+// reported with ctx's error instead of run. ctx is only checked between
+// dispatches so an already-started rename can't be left half done.
+func (r *Renamer) runOps(ctx context.Context, ops []execOp, workers int) map[string]*Result {
+	out := make(map[string]*Result, len(ops))
+
+	if workers <= 1 || len(ops) <= 1 {
+		for _, op := range ops {
+			if err := ctx.Err(); err != nil {
+				out[op.path] = &Result{From: op.from, To: op.to, Err: err}
+				continue
+			}
+			out[op.path] = r.execute(op)
+		}
+		return out
+	}
+
+	if workers > len(ops) {
+		workers = len(ops)
+	}
+
+	jobs := make(chan execOp)
+	finished := make(chan *Result, len(ops))
+
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	for i := 0; i < workers; i++ {
+		go func() {
+			defer wg.Done()
+			for op := range jobs {
+				finished <- r.execute(op)
+			}
+		}()
+	}
+
+	go func() {
+		defer close(jobs)
+		for _, op := range ops {
+			if err := ctx.Err(); err != nil {
+				finished <- &Result{From: op.from, To: op.to, Err: err}
+				continue
+			}
+			jobs <- op
+		}
+	}()
+
+	go func() {
+		wg.Wait()
+		close(finished)
+	}()
+
+	for res := range finished {
+		out[res.From] = res
+	}
+	return out
+}
+
+// execute performs op's filesystem mutation and reports the outcome.
+func (r *Renamer) execute(op execOp) *Result {
+	switch op.kind {
+	case opRemove:
+		if err := r.fs.Remove(op.from); err != nil {
+			return &Result{From: op.from, To: op.to, Err: err}
+		}
+		return &Result{From: op.from, To: op.to, Action: op.action}
+
+	default: // opRename
+		if err := r.doRename(op.from, op.to); err != nil {
+			return &Result{From: op.from, To: op.to, Err: err}
+		}
+		return &Result{From: op.from, To: op.to, Action: op.action}
+	}
+}
+
+// sameContent reports whether src and an already-on-disk to have
+// identical content, by comparing their ContentDigest. If to doesn't
+// exist yet (as when another path in the same batch is planned to take
+// it, since planning runs before any rename), it reports false rather
+// than an error: there's nothing to compare against yet.
+func (r *Renamer) sameContent(src, to string) (bool, error) {
+	if _, err := r.fs.Stat(to); err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	srcDigest, err := ContentDigest(r.fs, src)
+	if err != nil {
+		return false, err
+	}
+	toDigest, err := ContentDigest(r.fs, to)
+	if err != nil {
+		return false, err
+	}
+	return srcDigest == toDigest, nil
+}
+
+// suffixedName finds the first target-2, target-3, ... (inserted before
+// the extension) not already present in occupied.
+func suffixedName(target string, occupied map[string]bool) (string, error) {
+	ext := filepath.Ext(target)
+	base := target[:len(target)-len(ext)]
+
+	for n := 2; n <= maxSuffixAttempts; n++ {
+		candidate := fmt.Sprintf("%s-%d%s", base, n, ext)
+		if !occupied[candidate] {
+			return candidate, nil
+		}
+	}
+	return "", fmt.Errorf("could not find a unique suffix for %q after %d attempts", target, maxSuffixAttempts)
+}
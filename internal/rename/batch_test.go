@@ -0,0 +1,214 @@
+package rename
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/spf13/afero"
+)
+
+func resultFor(results []Result, from string) Result {
+	for _, r := range results {
+		if r.From == from {
+			return r
+		}
+	}
+	return Result{}
+}
+
+func TestApplyBatchSuffixesCollidingSources(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	writeFile(t, fs, "/docs/MY-FILE.TXT")
+	writeFile(t, fs, "/docs/My File.txt")
+	writeFile(t, fs, "/docs/my  file.txt")
+
+	paths := []string{"/docs/MY-FILE.TXT", "/docs/My File.txt", "/docs/my  file.txt"}
+	summary, results := New(fs).ApplyBatch(context.Background(), paths, Options{OnCollision: CollisionSuffix})
+
+	if summary.Renamed != 1 || summary.Suffixed != 2 || summary.Failed != 0 {
+		t.Fatalf("unexpected summary: %+v", summary)
+	}
+
+	winner := resultFor(results, "/docs/MY-FILE.TXT")
+	if winner.Action != ActionRenamed || winner.To != "/docs/my-file.txt" {
+		t.Fatalf("expected MY-FILE.TXT to win the plain name, got %+v", winner)
+	}
+
+	first := resultFor(results, "/docs/My File.txt")
+	second := resultFor(results, "/docs/my  file.txt")
+	if first.Action != ActionSuffixed || second.Action != ActionSuffixed {
+		t.Fatalf("expected both losers suffixed, got %+v and %+v", first, second)
+	}
+	if first.To == second.To {
+		t.Fatalf("expected distinct suffixed targets, both got %q", first.To)
+	}
+	for _, to := range []string{first.To, second.To} {
+		if to != "/docs/my-file-2.txt" && to != "/docs/my-file-3.txt" {
+			t.Fatalf("unexpected suffixed target %q", to)
+		}
+	}
+}
+
+func TestApplyBatchSuffixPrefersAlreadyCorrectName(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	writeFile(t, fs, "/docs/my-file.txt")
+	writeFile(t, fs, "/docs/My File.txt")
+
+	paths := []string{"/docs/my-file.txt", "/docs/My File.txt"}
+	_, results := New(fs).ApplyBatch(context.Background(), paths, Options{OnCollision: CollisionSuffix})
+
+	already := resultFor(results, "/docs/my-file.txt")
+	if already.Action != ActionUnchanged {
+		t.Fatalf("expected the already-correct file to be left alone, got %+v", already)
+	}
+
+	loser := resultFor(results, "/docs/My File.txt")
+	if loser.Action != ActionSuffixed || loser.To != "/docs/my-file-2.txt" {
+		t.Fatalf("expected the collision to be suffixed to my-file-2.txt, got %+v", loser)
+	}
+}
+
+func TestApplyBatchSuffixAgainstPreexistingFile(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	writeFile(t, fs, "/docs/my-file.txt") // not part of the batch, but already occupies the target
+	writeFile(t, fs, "/docs/My File.txt")
+
+	paths := []string{"/docs/My File.txt"}
+	summary, results := New(fs).ApplyBatch(context.Background(), paths, Options{OnCollision: CollisionSuffix})
+
+	if summary.Suffixed != 1 {
+		t.Fatalf("unexpected summary: %+v", summary)
+	}
+	got := resultFor(results, "/docs/My File.txt")
+	if got.Action != ActionSuffixed || got.To != "/docs/my-file-2.txt" {
+		t.Fatalf("expected suffixed to my-file-2.txt, got %+v", got)
+	}
+}
+
+func TestApplyBatchSkipCollisions(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	writeFile(t, fs, "/docs/MY-FILE.TXT")
+	writeFile(t, fs, "/docs/My File.txt")
+
+	paths := []string{"/docs/MY-FILE.TXT", "/docs/My File.txt"}
+	summary, results := New(fs).ApplyBatch(context.Background(), paths, Options{OnCollision: CollisionSkip})
+
+	if summary.Renamed != 1 || summary.Skipped != 1 {
+		t.Fatalf("unexpected summary: %+v", summary)
+	}
+	loser := resultFor(results, "/docs/My File.txt")
+	if loser.Action != ActionSkipped || loser.Err != nil {
+		t.Fatalf("expected skipped with no error, got %+v", loser)
+	}
+	if exists, _ := afero.Exists(fs, "/docs/My File.txt"); !exists {
+		t.Fatalf("expected the skipped file to remain in place")
+	}
+}
+
+func TestApplyBatchDedupesIdenticalContent(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	writeFile(t, fs, "/docs/my-file.txt")
+	writeFile(t, fs, "/docs/My File.txt") // writeFile always writes "content", so this is byte-identical
+
+	paths := []string{"/docs/My File.txt"}
+	summary, results := New(fs).ApplyBatch(context.Background(), paths, Options{OnCollision: CollisionDedupe})
+
+	if summary.Deduped != 1 {
+		t.Fatalf("unexpected summary: %+v", summary)
+	}
+	got := resultFor(results, "/docs/My File.txt")
+	if got.Action != ActionDeduped || got.Err != nil {
+		t.Fatalf("expected deduped with no error, got %+v", got)
+	}
+	if exists, _ := afero.Exists(fs, "/docs/My File.txt"); exists {
+		t.Fatalf("expected the duplicate source to be removed")
+	}
+	if exists, _ := afero.Exists(fs, "/docs/my-file.txt"); !exists {
+		t.Fatalf("expected the original target to remain in place")
+	}
+}
+
+func TestApplyBatchDedupeFallsBackToSuffixOnMismatch(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	if err := afero.WriteFile(fs, "/docs/my-file.txt", []byte("original"), 0o644); err != nil {
+		t.Fatalf("failed to write target: %v", err)
+	}
+	if err := afero.WriteFile(fs, "/docs/My File.txt", []byte("different"), 0o644); err != nil {
+		t.Fatalf("failed to write source: %v", err)
+	}
+
+	paths := []string{"/docs/My File.txt"}
+	summary, results := New(fs).ApplyBatch(context.Background(), paths, Options{OnCollision: CollisionDedupe})
+
+	if summary.Suffixed != 1 || summary.Deduped != 0 {
+		t.Fatalf("unexpected summary: %+v", summary)
+	}
+	got := resultFor(results, "/docs/My File.txt")
+	if got.Action != ActionSuffixed || got.To != "/docs/my-file-2.txt" {
+		t.Fatalf("expected suffixed to my-file-2.txt, got %+v", got)
+	}
+}
+
+func TestApplyBatchWorkersRenamesEverythingInOrder(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	paths := make([]string, 0, 20)
+	for i := 0; i < 20; i++ {
+		p := fmt.Sprintf("/docs/File %02d.TXT", i)
+		writeFile(t, fs, p)
+		paths = append(paths, p)
+	}
+
+	summary, results := New(fs).ApplyBatch(context.Background(), paths, Options{Workers: 4})
+
+	if summary.Renamed != len(paths) {
+		t.Fatalf("unexpected summary: %+v", summary)
+	}
+	if len(results) != len(paths) {
+		t.Fatalf("expected %d results, got %d", len(paths), len(results))
+	}
+	for i, res := range results {
+		if res.From != paths[i] {
+			t.Fatalf("expected results in input order, result %d was %q, wanted %q", i, res.From, paths[i])
+		}
+		if res.Action != ActionRenamed {
+			t.Fatalf("expected %q renamed, got %+v", res.From, res)
+		}
+	}
+}
+
+func TestApplyBatchWorkersStillDetectCollisions(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	writeFile(t, fs, "/docs/MY-FILE.TXT")
+	writeFile(t, fs, "/docs/My File.txt")
+
+	summary, results := New(fs).ApplyBatch(context.Background(), []string{"/docs/MY-FILE.TXT", "/docs/My File.txt"}, Options{
+		OnCollision: CollisionSuffix,
+		Workers:     4,
+	})
+
+	if summary.Renamed != 1 || summary.Suffixed != 1 {
+		t.Fatalf("unexpected summary: %+v", summary)
+	}
+	loser := resultFor(results, "/docs/My File.txt")
+	if loser.Action != ActionSuffixed || loser.To != "/docs/my-file-2.txt" {
+		t.Fatalf("expected the collision still suffixed under Workers, got %+v", loser)
+	}
+}
+
+func TestApplyBatchFailsCollisionsByDefault(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	writeFile(t, fs, "/docs/MY-FILE.TXT")
+	writeFile(t, fs, "/docs/My File.txt")
+
+	paths := []string{"/docs/MY-FILE.TXT", "/docs/My File.txt"}
+	summary, results := New(fs).ApplyBatch(context.Background(), paths, Options{})
+
+	if summary.Failed != 1 || summary.Renamed != 1 {
+		t.Fatalf("unexpected summary: %+v", summary)
+	}
+	loser := resultFor(results, "/docs/My File.txt")
+	if loser.Err == nil {
+		t.Fatalf("expected a collision error, got %+v", loser)
+	}
+}
@@ -0,0 +1,26 @@
+package rename
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+
+	"github.com/spf13/afero"
+)
+
+// ContentDigest returns the hex-encoded SHA-256 digest of the file at
+// path on fs, streaming it through the hash so the whole file never
+// needs to be held in memory at once.
+func ContentDigest(fs afero.Fs, path string) (string, error) {
+	f, err := fs.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
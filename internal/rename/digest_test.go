@@ -0,0 +1,54 @@
+package rename
+
+import (
+	"testing"
+
+	"github.com/spf13/afero"
+)
+
+func TestContentDigestMatchesForIdenticalContent(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	writeFile(t, fs, "/docs/a.txt")
+	writeFile(t, fs, "/docs/b.txt")
+
+	digestA, err := ContentDigest(fs, "/docs/a.txt")
+	if err != nil {
+		t.Fatalf("ContentDigest failed: %v", err)
+	}
+	digestB, err := ContentDigest(fs, "/docs/b.txt")
+	if err != nil {
+		t.Fatalf("ContentDigest failed: %v", err)
+	}
+	if digestA != digestB {
+		t.Fatalf("expected identical digests for identical content, got %q and %q", digestA, digestB)
+	}
+}
+
+func TestContentDigestDiffersForDifferentContent(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	if err := afero.WriteFile(fs, "/docs/a.txt", []byte("hello"), 0o644); err != nil {
+		t.Fatalf("failed to write a.txt: %v", err)
+	}
+	if err := afero.WriteFile(fs, "/docs/b.txt", []byte("world"), 0o644); err != nil {
+		t.Fatalf("failed to write b.txt: %v", err)
+	}
+
+	digestA, err := ContentDigest(fs, "/docs/a.txt")
+	if err != nil {
+		t.Fatalf("ContentDigest failed: %v", err)
+	}
+	digestB, err := ContentDigest(fs, "/docs/b.txt")
+	if err != nil {
+		t.Fatalf("ContentDigest failed: %v", err)
+	}
+	if digestA == digestB {
+		t.Fatalf("expected different digests for different content")
+	}
+}
+
+func TestContentDigestMissingFile(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	if _, err := ContentDigest(fs, "/docs/missing.txt"); err == nil {
+		t.Fatalf("expected an error for a missing file")
+	}
+}
@@ -0,0 +1,248 @@
+package rename
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/spf13/afero"
+)
+
+// JournalEntry records one successful rename, including the size and
+// modification time the renamed file had immediately afterward, so Undo
+// can refuse to reverse it if the file has since been touched.
+type JournalEntry struct {
+	Ts      time.Time `json:"ts"`
+	Cwd     string    `json:"cwd"`
+	From    string    `json:"from"`
+	To      string    `json:"to"`
+	Size    int64     `json:"size"`
+	ModTime time.Time `json:"mod_time"`
+}
+
+// Journal appends a JournalEntry per successful rename to a JSONL file,
+// so the renames a Renamer makes can be reversed later with Undo.
+// append is safe to call from multiple goroutines, since ApplyBatch may
+// execute renames across a worker pool.
+type Journal struct {
+	fs   afero.Fs
+	file afero.File
+	mu   sync.Mutex
+}
+
+// OpenJournal creates (or appends to) the JSONL journal file at path on
+// fs, creating its parent directory if needed.
+func OpenJournal(fs afero.Fs, path string) (*Journal, error) {
+	if err := fs.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, err
+	}
+	f, err := fs.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	return &Journal{fs: fs, file: f}, nil
+}
+
+// append records from -> to, reading the renamed file's current size
+// and modification time off of to.
+func (j *Journal) append(from, to string) error {
+	info, err := j.fs.Stat(to)
+	if err != nil {
+		return err
+	}
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		cwd = ""
+	}
+
+	line, err := json.Marshal(JournalEntry{
+		Ts:      time.Now(),
+		Cwd:     cwd,
+		From:    from,
+		To:      to,
+		Size:    info.Size(),
+		ModTime: info.ModTime(),
+	})
+	if err != nil {
+		return err
+	}
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	_, err = j.file.Write(append(line, '\n'))
+	return err
+}
+
+// Close closes the underlying journal file.
+func (j *Journal) Close() error {
+	return j.file.Close()
+}
+
+// ReadJournal reads every JournalEntry from the JSONL file at path, in
+// the order they were appended.
+func ReadJournal(fs afero.Fs, path string) ([]JournalEntry, error) {
+	f, err := fs.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var entries []JournalEntry
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+		var entry JournalEntry
+		if err := json.Unmarshal(line, &entry); err != nil {
+			return nil, fmt.Errorf("parse journal entry: %w", err)
+		}
+		entries = append(entries, entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// DefaultJournalDir returns the directory new journals are written to
+// by default: ~/.local/state/fnorm.
+func DefaultJournalDir() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".local", "state", "fnorm"), nil
+}
+
+// DefaultJournalPath returns the default path for a journal covering a
+// run starting at now: ~/.local/state/fnorm/journal-<timestamp>.jsonl.
+func DefaultJournalPath(now time.Time) (string, error) {
+	dir, err := DefaultJournalDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, fmt.Sprintf("journal-%s.jsonl", now.Format("20060102-150405"))), nil
+}
+
+// UndoAction describes what Undo did (or would do) with a single
+// journal entry.
+type UndoAction string
+
+const (
+	// UndoActionReversed means the entry's rename was reversed.
+	UndoActionReversed UndoAction = "reversed"
+	// UndoActionWouldReverse is UndoActionReversed's UndoOptions.DryRun
+	// counterpart.
+	UndoActionWouldReverse UndoAction = "would-reverse"
+	// UndoActionStale means the file currently at the entry's To no
+	// longer matches the size and modification time recorded when it
+	// was renamed, so Undo left it alone rather than risk clobbering
+	// whatever changed it since.
+	UndoActionStale UndoAction = "stale"
+	// UndoActionWrongDir means the entry's recorded Cwd no longer
+	// matches the process's current working directory, so its From/To
+	// (typically relative paths) would resolve against the wrong
+	// directory; Undo left it alone rather than risk renaming an
+	// unrelated file that happens to share a name.
+	UndoActionWrongDir UndoAction = "wrong-dir"
+)
+
+// UndoOptions controls how Undo reverses journal entries.
+type UndoOptions struct {
+	// DryRun reports what would be reversed without touching the
+	// filesystem.
+	DryRun bool
+}
+
+// UndoResult reports the outcome of reversing a single JournalEntry.
+type UndoResult struct {
+	Entry  JournalEntry
+	Action UndoAction
+	Err    error
+}
+
+// UndoSummary tallies the outcomes of an Undo call.
+type UndoSummary struct {
+	Reversed int
+	Skipped  int
+	Failed   int
+}
+
+// Undo reverses entries in LIFO order: the most recent rename comes
+// back first, since an earlier one in the same run may depend on the
+// name it freed up. Reversing itself bypasses r's journal, so undoing a
+// run never appends to (or truncates) its own journal.
+func (r *Renamer) Undo(ctx context.Context, entries []JournalEntry, opts UndoOptions) (UndoSummary, []UndoResult) {
+	summary := UndoSummary{}
+	results := make([]UndoResult, 0, len(entries))
+
+	for i := len(entries) - 1; i >= 0; i-- {
+		entry := entries[i]
+
+		var res UndoResult
+		if err := ctx.Err(); err != nil {
+			res = UndoResult{Entry: entry, Err: err}
+		} else {
+			res = r.undoOne(entry, opts)
+		}
+		results = append(results, res)
+
+		switch {
+		case res.Err != nil:
+			summary.Failed++
+		case res.Action == UndoActionReversed || res.Action == UndoActionWouldReverse:
+			summary.Reversed++
+		case res.Action == UndoActionStale || res.Action == UndoActionWrongDir:
+			summary.Skipped++
+		}
+	}
+	return summary, results
+}
+
+// undoOne reverses a single entry, refusing if the process's current
+// working directory no longer matches the one the entry was recorded
+// under (its From/To may be relative paths that would then resolve
+// somewhere else), or if the file at entry.To has been modified since
+// the rename it's reversing.
+func (r *Renamer) undoOne(entry JournalEntry, opts UndoOptions) UndoResult {
+	if entry.Cwd != "" {
+		cwd, err := os.Getwd()
+		if err != nil {
+			return UndoResult{Entry: entry, Err: err}
+		}
+		if cwd != entry.Cwd {
+			return UndoResult{Entry: entry, Action: UndoActionWrongDir}
+		}
+	}
+
+	info, err := r.fs.Stat(entry.To)
+	if err != nil {
+		return UndoResult{Entry: entry, Err: err}
+	}
+	if info.Size() != entry.Size || !info.ModTime().Equal(entry.ModTime) {
+		return UndoResult{Entry: entry, Action: UndoActionStale}
+	}
+
+	if opts.DryRun {
+		return UndoResult{Entry: entry, Action: UndoActionWouldReverse}
+	}
+
+	if isCaseOnlyChange(entry.To, entry.From) {
+		if err := r.renameCaseOnly(entry.To, entry.From); err != nil {
+			return UndoResult{Entry: entry, Err: err}
+		}
+		return UndoResult{Entry: entry, Action: UndoActionReversed}
+	}
+	if err := r.fs.Rename(entry.To, entry.From); err != nil {
+		return UndoResult{Entry: entry, Err: err}
+	}
+	return UndoResult{Entry: entry, Action: UndoActionReversed}
+}
@@ -0,0 +1,175 @@
+package rename
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/spf13/afero"
+)
+
+func TestJournalAppendAndReadJournalRoundTrip(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	writeFile(t, fs, "/docs/My File.txt")
+
+	j, err := OpenJournal(fs, "/state/journal.jsonl")
+	if err != nil {
+		t.Fatalf("OpenJournal failed: %v", err)
+	}
+	New(fs).WithJournal(j).Apply(context.Background(), []string{"/docs/My File.txt"}, Options{})
+	if err := j.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	entries, err := ReadJournal(fs, "/state/journal.jsonl")
+	if err != nil {
+		t.Fatalf("ReadJournal failed: %v", err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("expected 1 journal entry, got %d", len(entries))
+	}
+	entry := entries[0]
+	if entry.From != "/docs/My File.txt" || entry.To != "/docs/my-file.txt" {
+		t.Fatalf("unexpected journal entry: %+v", entry)
+	}
+	if entry.Size == 0 {
+		t.Fatalf("expected a non-zero recorded size")
+	}
+}
+
+func TestJournalNotAppendedWithoutWithJournal(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	writeFile(t, fs, "/docs/My File.txt")
+
+	New(fs).Apply(context.Background(), []string{"/docs/My File.txt"}, Options{})
+
+	if exists, _ := afero.Exists(fs, "/state/journal.jsonl"); exists {
+		t.Fatalf("expected no journal file without WithJournal")
+	}
+}
+
+func TestUndoReversesInLIFOOrder(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	writeFile(t, fs, "/docs/My File.txt")
+	writeFile(t, fs, "/docs/Another One.txt")
+
+	j, err := OpenJournal(fs, "/state/journal.jsonl")
+	if err != nil {
+		t.Fatalf("OpenJournal failed: %v", err)
+	}
+	renamer := New(fs).WithJournal(j)
+	renamer.Apply(context.Background(), []string{"/docs/My File.txt", "/docs/Another One.txt"}, Options{})
+	if err := j.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	entries, err := ReadJournal(fs, "/state/journal.jsonl")
+	if err != nil {
+		t.Fatalf("ReadJournal failed: %v", err)
+	}
+
+	summary, results := New(fs).Undo(context.Background(), entries, UndoOptions{})
+	if summary.Reversed != 2 || summary.Failed != 0 || summary.Skipped != 0 {
+		t.Fatalf("unexpected summary: %+v", summary)
+	}
+	if results[0].Entry.From != "/docs/Another One.txt" {
+		t.Fatalf("expected the most recent rename to be reversed first, got %+v", results[0])
+	}
+	if exists, _ := afero.Exists(fs, "/docs/My File.txt"); !exists {
+		t.Fatalf("expected My File.txt restored")
+	}
+	if exists, _ := afero.Exists(fs, "/docs/Another One.txt"); !exists {
+		t.Fatalf("expected Another One.txt restored")
+	}
+}
+
+func TestUndoRefusesStaleTarget(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	writeFile(t, fs, "/docs/My File.txt")
+
+	j, err := OpenJournal(fs, "/state/journal.jsonl")
+	if err != nil {
+		t.Fatalf("OpenJournal failed: %v", err)
+	}
+	renamer := New(fs).WithJournal(j)
+	renamer.Apply(context.Background(), []string{"/docs/My File.txt"}, Options{})
+	if err := j.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	entries, err := ReadJournal(fs, "/state/journal.jsonl")
+	if err != nil {
+		t.Fatalf("ReadJournal failed: %v", err)
+	}
+
+	// Touch the renamed file so it no longer matches what was journaled.
+	if err := afero.WriteFile(fs, "/docs/my-file.txt", []byte("changed"), 0o644); err != nil {
+		t.Fatalf("failed to modify renamed file: %v", err)
+	}
+
+	summary, results := New(fs).Undo(context.Background(), entries, UndoOptions{})
+	if summary.Skipped != 1 || summary.Reversed != 0 {
+		t.Fatalf("unexpected summary: %+v", summary)
+	}
+	if results[0].Action != UndoActionStale {
+		t.Fatalf("expected stale action, got %+v", results[0])
+	}
+	if exists, _ := afero.Exists(fs, "/docs/my-file.txt"); !exists {
+		t.Fatalf("expected the modified file to remain at its renamed location")
+	}
+}
+
+func TestUndoRefusesWrongDir(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	writeFile(t, fs, "/docs/my-file.txt")
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("Getwd failed: %v", err)
+	}
+	entries := []JournalEntry{
+		{Cwd: cwd + "/elsewhere", From: "My File.txt", To: "my-file.txt"},
+	}
+
+	summary, results := New(fs).Undo(context.Background(), entries, UndoOptions{})
+	if summary.Skipped != 1 || summary.Reversed != 0 {
+		t.Fatalf("unexpected summary: %+v", summary)
+	}
+	if results[0].Action != UndoActionWrongDir {
+		t.Fatalf("expected wrong-dir action, got %+v", results[0])
+	}
+	if exists, _ := afero.Exists(fs, "/docs/my-file.txt"); !exists {
+		t.Fatalf("expected the file to remain at its renamed location")
+	}
+}
+
+func TestUndoDryRun(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	writeFile(t, fs, "/docs/My File.txt")
+
+	j, err := OpenJournal(fs, "/state/journal.jsonl")
+	if err != nil {
+		t.Fatalf("OpenJournal failed: %v", err)
+	}
+	renamer := New(fs).WithJournal(j)
+	renamer.Apply(context.Background(), []string{"/docs/My File.txt"}, Options{})
+	if err := j.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	entries, err := ReadJournal(fs, "/state/journal.jsonl")
+	if err != nil {
+		t.Fatalf("ReadJournal failed: %v", err)
+	}
+
+	summary, results := New(fs).Undo(context.Background(), entries, UndoOptions{DryRun: true})
+	if summary.Reversed != 1 {
+		t.Fatalf("unexpected summary: %+v", summary)
+	}
+	if results[0].Action != UndoActionWouldReverse {
+		t.Fatalf("expected would-reverse action, got %+v", results[0])
+	}
+	if exists, _ := afero.Exists(fs, "/docs/my-file.txt"); !exists {
+		t.Fatalf("expected the renamed file to remain untouched in a dry run")
+	}
+}
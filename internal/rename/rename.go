@@ -0,0 +1,203 @@
+// Package rename provides an embeddable, filesystem-agnostic API for
+// renaming files to their normalized names.
+package rename
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/afero"
+
+	"github.com/laydros/fnorm/internal/normalize"
+)
+
+// Action describes what Apply did (or would do) with a single path.
+type Action string
+
+const (
+	// ActionRenamed means the path was renamed on disk.
+	ActionRenamed Action = "renamed"
+	// ActionWouldRename means the path would be renamed, but Options.DryRun
+	// was set so no filesystem change was made.
+	ActionWouldRename Action = "would-rename"
+	// ActionUnchanged means the normalized name is identical to the
+	// original, so nothing needed to happen.
+	ActionUnchanged Action = "unchanged"
+	// ActionSkipped means the path was left alone: it was a directory and
+	// Options.IncludeDirs was unset, or ApplyBatch skipped it under
+	// CollisionSkip.
+	ActionSkipped Action = "skipped"
+)
+
+// tempSuffix marks the intermediate name used for case-only renames on
+// case-insensitive filesystems.
+const tempSuffix = ".fnorm-tmp"
+
+// Result reports the outcome of normalizing and renaming a single path.
+type Result struct {
+	From   string
+	To     string
+	Action Action
+	Err    error
+	// IsDir reports whether From was a directory, so callers walking a
+	// tree can report directory and file renames distinctly.
+	IsDir bool
+}
+
+// Options controls how Apply treats each path.
+type Options struct {
+	// DryRun reports what would happen without touching the filesystem.
+	DryRun bool
+	// Force overwrites an existing target instead of failing with
+	// os.ErrExist.
+	Force bool
+	// IncludeDirs renames directories the same way as files instead of
+	// reporting ActionSkipped for them. Callers doing a recursive walk
+	// set this so directory names get normalized alongside file names.
+	IncludeDirs bool
+	// OnCollision selects how ApplyBatch disambiguates two or more paths
+	// that normalize to the same target. The zero value is
+	// CollisionFail, matching Apply's existing os.ErrExist behavior.
+	OnCollision CollisionPolicy
+	// Workers caps how many renames ApplyBatch performs concurrently.
+	// The zero value and 1 both mean "run serially, one at a time";
+	// output order is unaffected either way, since results are always
+	// reported in the order paths were given.
+	Workers int
+}
+
+// Renamer renames files to their normalized names against an afero.Fs,
+// so callers can swap in afero.NewMemMapFs() for tests and
+// afero.NewOsFs() for the real filesystem.
+type Renamer struct {
+	fs        afero.Fs
+	normalize func(string) string
+	journal   *Journal
+}
+
+// New returns a Renamer backed by fs, using normalize.Normalize (the
+// default profile) to compute target names.
+func New(fs afero.Fs) *Renamer {
+	return &Renamer{fs: fs, normalize: normalize.Normalize}
+}
+
+// NewWithProfile returns a Renamer backed by fs that computes target
+// names with normalize.NormalizeProfile(name, profile) instead of the
+// default profile, so callers can honor a profile loaded from a config
+// file.
+func NewWithProfile(fs afero.Fs, profile normalize.Profile) *Renamer {
+	return &Renamer{
+		fs: fs,
+		normalize: func(name string) string {
+			return normalize.NormalizeProfile(name, profile)
+		},
+	}
+}
+
+// WithJournal configures r to append every successful rename to j, so a
+// run's renames can be reversed later with j's Renamer.Undo. It returns
+// r so the call can be chained onto New or NewWithProfile. Passing nil
+// disables journaling, which is the default.
+func (r *Renamer) WithJournal(j *Journal) *Renamer {
+	r.journal = j
+	return r
+}
+
+// Apply normalizes and renames each of paths, stopping early only if ctx
+// is canceled. Every path gets its own Result, so a single failure
+// doesn't prevent the rest of the batch from being processed.
+func (r *Renamer) Apply(ctx context.Context, paths []string, opts Options) []Result {
+	results := make([]Result, 0, len(paths))
+	for _, p := range paths {
+		if err := ctx.Err(); err != nil {
+			results = append(results, Result{From: p, Err: err})
+			continue
+		}
+		results = append(results, r.applyOne(p, opts))
+	}
+	return results
+}
+
+func (r *Renamer) applyOne(p string, opts Options) Result {
+	info, err := r.fs.Stat(p)
+	if err != nil {
+		return Result{From: p, Err: err}
+	}
+	isDir := info.IsDir()
+	if isDir && !opts.IncludeDirs {
+		return Result{From: p, Action: ActionSkipped, IsDir: isDir}
+	}
+
+	dir := filepath.Dir(p)
+	filename := filepath.Base(p)
+	normalized := r.normalize(filename)
+
+	if filename == normalized {
+		return Result{From: p, To: p, Action: ActionUnchanged, IsDir: isDir}
+	}
+
+	to := filepath.Join(dir, normalized)
+
+	if opts.DryRun {
+		return Result{From: p, To: to, Action: ActionWouldRename, IsDir: isDir}
+	}
+
+	if !isCaseOnlyChange(p, to) {
+		if _, err := r.fs.Stat(to); err == nil && !opts.Force {
+			return Result{From: p, To: to, Err: os.ErrExist, IsDir: isDir}
+		}
+	}
+
+	if err := r.doRename(p, to); err != nil {
+		return Result{From: p, To: to, Err: err, IsDir: isDir}
+	}
+	return Result{From: p, To: to, Action: ActionRenamed, IsDir: isDir}
+}
+
+// doRename performs the actual filesystem rename from -> to, using the
+// two-step case-only dance on case-insensitive filesystems, and appends
+// the rename to r.journal (if one was set via WithJournal) so it can be
+// reversed later with Undo.
+func (r *Renamer) doRename(from, to string) error {
+	if isCaseOnlyChange(from, to) {
+		if err := r.renameCaseOnly(from, to); err != nil {
+			return err
+		}
+	} else if err := r.fs.Rename(from, to); err != nil {
+		return err
+	}
+
+	if r.journal == nil {
+		return nil
+	}
+	return r.journal.append(from, to)
+}
+
+// isCaseOnlyChange returns true if the old and new paths differ only in
+// case, which needs a two-step rename on case-insensitive filesystems.
+func isCaseOnlyChange(from, to string) bool {
+	return strings.EqualFold(from, to) && from != to
+}
+
+// renameCaseOnly works around case-insensitive filesystems (macOS,
+// Windows) treating "Foo.txt" and "foo.txt" as the same path by renaming
+// through a temporary intermediate name.
+func (r *Renamer) renameCaseOnly(from, to string) error {
+	tmp := from + tempSuffix
+
+	if err := r.fs.Rename(from, tmp); err != nil {
+		return err
+	}
+
+	if err := r.fs.Rename(tmp, to); err != nil {
+		if restoreErr := r.fs.Rename(tmp, from); restoreErr != nil {
+			return errors.Join(err, restoreErr)
+		}
+		return err
+	}
+
+	return nil
+}
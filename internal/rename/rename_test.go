@@ -0,0 +1,216 @@
+package rename
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/afero"
+
+	"github.com/laydros/fnorm/internal/normalize"
+)
+
+// lowerCaseFs wraps an afero.Fs and lower-cases every path before
+// touching the underlying filesystem, simulating a case-insensitive
+// filesystem (macOS default, Windows) on top of afero's case-sensitive
+// MemMapFs.
+type lowerCaseFs struct {
+	afero.Fs
+}
+
+// newLowerCaseFs wraps base so every lookup is case-insensitive.
+func newLowerCaseFs(base afero.Fs) afero.Fs {
+	return lowerCaseFs{Fs: base}
+}
+
+func (fs lowerCaseFs) Create(name string) (afero.File, error) {
+	return fs.Fs.Create(strings.ToLower(name))
+}
+
+func (fs lowerCaseFs) Mkdir(name string, perm os.FileMode) error {
+	return fs.Fs.Mkdir(strings.ToLower(name), perm)
+}
+
+func (fs lowerCaseFs) MkdirAll(path string, perm os.FileMode) error {
+	return fs.Fs.MkdirAll(strings.ToLower(path), perm)
+}
+
+func (fs lowerCaseFs) Open(name string) (afero.File, error) {
+	return fs.Fs.Open(strings.ToLower(name))
+}
+
+func (fs lowerCaseFs) OpenFile(name string, flag int, perm os.FileMode) (afero.File, error) {
+	return fs.Fs.OpenFile(strings.ToLower(name), flag, perm)
+}
+
+func (fs lowerCaseFs) Remove(name string) error {
+	return fs.Fs.Remove(strings.ToLower(name))
+}
+
+func (fs lowerCaseFs) RemoveAll(path string) error {
+	return fs.Fs.RemoveAll(strings.ToLower(path))
+}
+
+func (fs lowerCaseFs) Rename(oldname, newname string) error {
+	return fs.Fs.Rename(strings.ToLower(oldname), strings.ToLower(newname))
+}
+
+func (fs lowerCaseFs) Stat(name string) (os.FileInfo, error) {
+	return fs.Fs.Stat(strings.ToLower(name))
+}
+
+func (fs lowerCaseFs) Chmod(name string, mode os.FileMode) error {
+	return fs.Fs.Chmod(strings.ToLower(name), mode)
+}
+
+func (fs lowerCaseFs) Chtimes(name string, atime, mtime time.Time) error {
+	return fs.Fs.Chtimes(strings.ToLower(name), atime, mtime)
+}
+
+func writeFile(t *testing.T, fs afero.Fs, path string) {
+	t.Helper()
+	if err := afero.WriteFile(fs, path, []byte("content"), 0o644); err != nil {
+		t.Fatalf("failed to write %s: %v", path, err)
+	}
+}
+
+func TestApplyRenamesFiles(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	writeFile(t, fs, "/docs/My File.txt")
+
+	results := New(fs).Apply(context.Background(), []string{"/docs/My File.txt"}, Options{})
+
+	if len(results) != 1 {
+		t.Fatalf("expected 1 result, got %d", len(results))
+	}
+	got := results[0]
+	if got.Action != ActionRenamed || got.Err != nil {
+		t.Fatalf("expected renamed with no error, got %+v", got)
+	}
+	if got.To != "/docs/my-file.txt" {
+		t.Fatalf("expected /docs/my-file.txt, got %q", got.To)
+	}
+	if exists, _ := afero.Exists(fs, "/docs/my-file.txt"); !exists {
+		t.Fatalf("expected target file to exist")
+	}
+	if exists, _ := afero.Exists(fs, "/docs/My File.txt"); exists {
+		t.Fatalf("expected source file to be gone")
+	}
+}
+
+func TestApplyUnchanged(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	writeFile(t, fs, "/docs/already-normalized.txt")
+
+	results := New(fs).Apply(context.Background(), []string{"/docs/already-normalized.txt"}, Options{})
+
+	if results[0].Action != ActionUnchanged {
+		t.Fatalf("expected unchanged, got %+v", results[0])
+	}
+}
+
+func TestApplySkipsDirectories(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	if err := fs.MkdirAll("/docs/My Folder", 0o755); err != nil {
+		t.Fatalf("failed to create directory: %v", err)
+	}
+
+	results := New(fs).Apply(context.Background(), []string{"/docs/My Folder"}, Options{})
+
+	if results[0].Action != ActionSkipped {
+		t.Fatalf("expected skipped, got %+v", results[0])
+	}
+}
+
+func TestApplyDryRun(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	writeFile(t, fs, "/docs/My File.txt")
+
+	results := New(fs).Apply(context.Background(), []string{"/docs/My File.txt"}, Options{DryRun: true})
+
+	if results[0].Action != ActionWouldRename {
+		t.Fatalf("expected would-rename, got %+v", results[0])
+	}
+	if exists, _ := afero.Exists(fs, "/docs/My File.txt"); !exists {
+		t.Fatalf("expected source file to still exist in dry-run mode")
+	}
+	if exists, _ := afero.Exists(fs, "/docs/my-file.txt"); exists {
+		t.Fatalf("expected target file not to exist in dry-run mode")
+	}
+}
+
+func TestApplyCollisionDetection(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	writeFile(t, fs, "/docs/My File.txt")
+	writeFile(t, fs, "/docs/my-file.txt")
+
+	results := New(fs).Apply(context.Background(), []string{"/docs/My File.txt"}, Options{})
+
+	if results[0].Err == nil {
+		t.Fatalf("expected collision error, got %+v", results[0])
+	}
+}
+
+func TestApplyForceOverwritesCollision(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	writeFile(t, fs, "/docs/My File.txt")
+	writeFile(t, fs, "/docs/my-file.txt")
+
+	results := New(fs).Apply(context.Background(), []string{"/docs/My File.txt"}, Options{Force: true})
+
+	if results[0].Action != ActionRenamed || results[0].Err != nil {
+		t.Fatalf("expected forced rename, got %+v", results[0])
+	}
+}
+
+func TestApplyIncludeDirsRenamesDirectories(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	if err := fs.MkdirAll("/docs/My Folder", 0o755); err != nil {
+		t.Fatalf("failed to create directory: %v", err)
+	}
+
+	results := New(fs).Apply(context.Background(), []string{"/docs/My Folder"}, Options{IncludeDirs: true})
+
+	if results[0].Action != ActionRenamed || results[0].Err != nil {
+		t.Fatalf("expected renamed, got %+v", results[0])
+	}
+	if results[0].To != "/docs/my-folder" {
+		t.Fatalf("expected /docs/my-folder, got %q", results[0].To)
+	}
+	if exists, _ := afero.DirExists(fs, "/docs/my-folder"); !exists {
+		t.Fatalf("expected renamed directory to exist")
+	}
+}
+
+func TestApplyCaseOnlyRenameOnCaseInsensitiveFs(t *testing.T) {
+	fs := newLowerCaseFs(afero.NewMemMapFs())
+	writeFile(t, fs, "/docs/REPORT.TXT")
+
+	results := New(fs).Apply(context.Background(), []string{"/docs/REPORT.TXT"}, Options{})
+
+	if results[0].Action != ActionRenamed || results[0].Err != nil {
+		t.Fatalf("expected renamed with no error, got %+v", results[0])
+	}
+	if content, err := afero.ReadFile(fs, "/docs/report.txt"); err != nil || string(content) != "content" {
+		t.Fatalf("expected renamed file to keep its content, got %q, err %v", content, err)
+	}
+}
+
+func TestApplyWithProfileUsesCustomSeparator(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	writeFile(t, fs, "/docs/My File.txt")
+
+	profile := normalize.DefaultProfile()
+	profile.Separator = "_"
+
+	results := NewWithProfile(fs, profile).Apply(context.Background(), []string{"/docs/My File.txt"}, Options{})
+
+	if results[0].Action != ActionRenamed || results[0].Err != nil {
+		t.Fatalf("expected renamed with no error, got %+v", results[0])
+	}
+	if results[0].To != "/docs/my_file.txt" {
+		t.Fatalf("expected /docs/my_file.txt, got %q", results[0].To)
+	}
+}
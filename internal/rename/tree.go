@@ -0,0 +1,123 @@
+package rename
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/afero"
+)
+
+// TreeOptions controls how ApplyTree walks and normalizes a directory
+// tree, on top of the batch-wide collision handling Options already
+// provides.
+type TreeOptions struct {
+	Options
+	// SkipHidden skips dotfiles and dot-directories.
+	SkipHidden bool
+	// Include, if set, only renames files whose base name matches this
+	// glob pattern (filepath.Match syntax). Directories are always
+	// walked regardless of Include/Exclude so files beneath them are
+	// still reached.
+	Include string
+	// Exclude, if set, skips files whose base name matches this glob
+	// pattern.
+	Exclude string
+}
+
+// TreeEntry is a single file or directory discovered by ApplyTree's walk.
+type TreeEntry struct {
+	Path  string
+	IsDir bool
+}
+
+// ApplyTree walks root against r's filesystem and normalizes every file
+// (and, with Options.IncludeDirs, every directory) it contains. Entries
+// are renamed bottom-up, deepest first, so renaming a parent directory
+// can't invalidate the paths of children still waiting to be renamed.
+// Collisions are detected per directory level via ApplyBatch, so two
+// sibling entries renamed earlier in the same walk are taken into
+// account, not just files already on disk before the walk started.
+func (r *Renamer) ApplyTree(ctx context.Context, root string, opts TreeOptions) (Summary, []Result, error) {
+	entries, err := r.walkTree(root, opts)
+	if err != nil {
+		return Summary{}, nil, err
+	}
+
+	byDepth := map[int][]string{}
+	var depths []int
+	for _, e := range entries {
+		depth := strings.Count(strings.TrimPrefix(e.Path, root), string(filepath.Separator))
+		if _, seen := byDepth[depth]; !seen {
+			depths = append(depths, depth)
+		}
+		byDepth[depth] = append(byDepth[depth], e.Path)
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(depths)))
+
+	var summary Summary
+	var results []Result
+	for _, depth := range depths {
+		if err := ctx.Err(); err != nil {
+			return summary, results, err
+		}
+		levelSummary, levelResults := r.ApplyBatch(ctx, byDepth[depth], opts.Options)
+		results = append(results, levelResults...)
+		summary.Renamed += levelSummary.Renamed
+		summary.Suffixed += levelSummary.Suffixed
+		summary.Deduped += levelSummary.Deduped
+		summary.Skipped += levelSummary.Skipped
+		summary.Unchanged += levelSummary.Unchanged
+		summary.Failed += levelSummary.Failed
+	}
+	return summary, results, nil
+}
+
+// walkTree lists every file and directory under root, skipping symlinks
+// and anything rejected by opts.SkipHidden/Include/Exclude.
+func (r *Renamer) walkTree(root string, opts TreeOptions) ([]TreeEntry, error) {
+	var entries []TreeEntry
+
+	err := afero.Walk(r.fs, root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.Mode()&os.ModeSymlink != 0 {
+			return nil
+		}
+		if path != root && opts.SkipHidden && strings.HasPrefix(info.Name(), ".") {
+			if info.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !info.IsDir() && !matchesGlobFilters(info.Name(), opts.Include, opts.Exclude) {
+			return nil
+		}
+
+		entries = append(entries, TreeEntry{Path: path, IsDir: info.IsDir()})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// matchesGlobFilters reports whether name passes the include and
+// exclude glob patterns, if set.
+func matchesGlobFilters(name, include, exclude string) bool {
+	if include != "" {
+		if ok, _ := filepath.Match(include, name); !ok {
+			return false
+		}
+	}
+	if exclude != "" {
+		if ok, _ := filepath.Match(exclude, name); ok {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,143 @@
+package rename
+
+import (
+	"context"
+	"testing"
+
+	"github.com/spf13/afero"
+)
+
+func TestApplyTreeRenamesBottomUp(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	writeFile(t, fs, "/root/My Project/Sub Folder/Deep File.txt")
+	writeFile(t, fs, "/root/My Project/Top File.txt")
+
+	summary, _, err := New(fs).ApplyTree(context.Background(), "/root/My Project", TreeOptions{
+		Options: Options{IncludeDirs: true},
+	})
+	if err != nil {
+		t.Fatalf("ApplyTree failed: %v", err)
+	}
+	if summary.Renamed != 4 {
+		t.Fatalf("expected 4 renames (root dir, sub dir, and both files), got %+v", summary)
+	}
+
+	if exists, _ := afero.Exists(fs, "/root/my-project/sub-folder/deep-file.txt"); !exists {
+		t.Fatalf("expected nested file renamed under renamed parent directories")
+	}
+	if exists, _ := afero.Exists(fs, "/root/my-project/top-file.txt"); !exists {
+		t.Fatalf("expected top-level file renamed")
+	}
+}
+
+func TestApplyTreeSkipsDirectoriesWithoutIncludeDirs(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	writeFile(t, fs, "/root/My Project/Top File.txt")
+
+	_, results, err := New(fs).ApplyTree(context.Background(), "/root/My Project", TreeOptions{})
+	if err != nil {
+		t.Fatalf("ApplyTree failed: %v", err)
+	}
+
+	if exists, _ := afero.DirExists(fs, "/root/My Project"); !exists {
+		t.Fatalf("expected directory to be left in place without IncludeDirs")
+	}
+	dirResult := resultFor(results, "/root/My Project")
+	if dirResult.Action != ActionSkipped {
+		t.Fatalf("expected the directory itself to be skipped, got %+v", dirResult)
+	}
+
+	if exists, _ := afero.Exists(fs, "/root/My Project/top-file.txt"); !exists {
+		t.Fatalf("expected the file inside to still be renamed")
+	}
+}
+
+func TestApplyTreeDetectsCollisionsPerLevel(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	writeFile(t, fs, "/root/MY-FILE.TXT")
+	writeFile(t, fs, "/root/My File.txt")
+
+	summary, results, err := New(fs).ApplyTree(context.Background(), "/root", TreeOptions{
+		Options: Options{OnCollision: CollisionSuffix},
+	})
+	if err != nil {
+		t.Fatalf("ApplyTree failed: %v", err)
+	}
+	if summary.Renamed != 1 || summary.Suffixed != 1 {
+		t.Fatalf("unexpected summary: %+v", summary)
+	}
+
+	loser := resultFor(results, "/root/My File.txt")
+	if loser.Action != ActionSuffixed || loser.To != "/root/my-file-2.txt" {
+		t.Fatalf("expected the collision to be suffixed, got %+v", loser)
+	}
+}
+
+func TestApplyTreeDedupesPerLevel(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	writeFile(t, fs, "/root/my-file.txt")
+	writeFile(t, fs, "/root/My File.txt") // writeFile always writes "content", so this is byte-identical
+
+	summary, results, err := New(fs).ApplyTree(context.Background(), "/root", TreeOptions{
+		Options: Options{OnCollision: CollisionDedupe},
+	})
+	if err != nil {
+		t.Fatalf("ApplyTree failed: %v", err)
+	}
+	if summary.Deduped != 1 {
+		t.Fatalf("expected 1 deduped in the summary, got %+v", summary)
+	}
+
+	loser := resultFor(results, "/root/My File.txt")
+	if loser.Action != ActionDeduped || loser.Err != nil {
+		t.Fatalf("expected the duplicate to be deduped, got %+v", loser)
+	}
+	if exists, _ := afero.Exists(fs, "/root/My File.txt"); exists {
+		t.Fatalf("expected the duplicate source to be removed")
+	}
+}
+
+func TestApplyTreeSkipsHidden(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	writeFile(t, fs, "/root/.git/Config File.txt")
+	writeFile(t, fs, "/root/Visible File.txt")
+
+	_, _, err := New(fs).ApplyTree(context.Background(), "/root", TreeOptions{
+		SkipHidden: true,
+	})
+	if err != nil {
+		t.Fatalf("ApplyTree failed: %v", err)
+	}
+
+	if exists, _ := afero.Exists(fs, "/root/.git/Config File.txt"); !exists {
+		t.Fatalf("expected hidden directory's contents to be left untouched")
+	}
+	if exists, _ := afero.Exists(fs, "/root/visible-file.txt"); !exists {
+		t.Fatalf("expected visible file to be renamed")
+	}
+}
+
+func TestApplyTreeIncludeExcludeFilters(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	writeFile(t, fs, "/root/Keep Me.txt")
+	writeFile(t, fs, "/root/Skip Me.log")
+	writeFile(t, fs, "/root/Drop Me.txt")
+
+	_, _, err := New(fs).ApplyTree(context.Background(), "/root", TreeOptions{
+		Include: "*.txt",
+		Exclude: "Drop*",
+	})
+	if err != nil {
+		t.Fatalf("ApplyTree failed: %v", err)
+	}
+
+	if exists, _ := afero.Exists(fs, "/root/keep-me.txt"); !exists {
+		t.Fatalf("expected included file to be renamed")
+	}
+	if exists, _ := afero.Exists(fs, "/root/Skip Me.log"); !exists {
+		t.Fatalf("expected file not matching Include to be left untouched")
+	}
+	if exists, _ := afero.Exists(fs, "/root/Drop Me.txt"); !exists {
+		t.Fatalf("expected excluded file to be left untouched")
+	}
+}
@@ -2,6 +2,69 @@ package fnorm
 
 import "testing"
 
+func TestNormalizeBytes(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    []byte
+		expected string
+	}{
+		{
+			name:     "no BOM treated as UTF-8",
+			input:    []byte("My File.txt"),
+			expected: "my-file.txt",
+		},
+		{
+			name:     "UTF-8 BOM stripped",
+			input:    append([]byte{0xEF, 0xBB, 0xBF}, []byte("My File.txt")...),
+			expected: "my-file.txt",
+		},
+		{
+			name:     "UTF-16LE BOM decoded",
+			input:    append([]byte{0xFF, 0xFE}, encodeUTF16LE("My File.txt")...),
+			expected: "my-file.txt",
+		},
+		{
+			name:     "UTF-16BE BOM decoded",
+			input:    append([]byte{0xFE, 0xFF}, encodeUTF16BE("My File.txt")...),
+			expected: "my-file.txt",
+		},
+		{
+			name:     "invalid UTF-16 surrogate replaced, then trimmed like any trailing hyphen",
+			input:    append([]byte{0xFF, 0xFE}, append(encodeUTF16LE("bad"), 0x00, 0xD8)...),
+			expected: "bad",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got := NormalizeBytes(tc.input)
+			if got != tc.expected {
+				t.Fatalf("expected %q, got %q", tc.expected, got)
+			}
+		})
+	}
+}
+
+// encodeUTF16LE encodes s as raw little-endian UTF-16 code units, for
+// building BOM-prefixed test fixtures without importing a UTF-16 codec
+// into the test itself.
+func encodeUTF16LE(s string) []byte {
+	var buf []byte
+	for _, r := range s {
+		buf = append(buf, byte(r), byte(r>>8))
+	}
+	return buf
+}
+
+// encodeUTF16BE encodes s as raw big-endian UTF-16 code units.
+func encodeUTF16BE(s string) []byte {
+	var buf []byte
+	for _, r := range s {
+		buf = append(buf, byte(r>>8), byte(r))
+	}
+	return buf
+}
+
 func TestNormalize(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -0,0 +1,342 @@
+package normalize
+
+import (
+	"bytes"
+	"io"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"unicode"
+
+	encunicode "golang.org/x/text/encoding/unicode"
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+const (
+	spaceReplacer                   = "-"
+	forbiddenCharsPattern           = `[^a-z0-9\-_.]`
+	forbiddenCharsAllowSlashPattern = `[^a-z0-9\-_./]`
+)
+
+var (
+	forbiddenCharsRe           = regexp.MustCompile(forbiddenCharsPattern)
+	forbiddenCharsAllowSlashRe = regexp.MustCompile(forbiddenCharsAllowSlashPattern)
+	multiHyphenRe              = regexp.MustCompile(`-+`)
+
+	defaultReplacements = map[string]string{
+		"/": "-or-",
+		"&": "-and-",
+		"@": "-at-",
+		"%": "-percent",
+	}
+
+	// nonSpacingMarks strips combining diacritics produced by NFKD
+	// decomposition (e.g. the combining acute accent in "é" -> "e´").
+	nonSpacingMarks = runes.Remove(runes.In(unicode.Mn))
+)
+
+// Profile selects what kind of string a Normalizer slugs: a single file
+// name, a whole path, or a standalone URL slug.
+type Profile int
+
+const (
+	// ProfileFilename normalizes a single file name: its extension
+	// survives untouched (lowercased) while everything before it is
+	// slugged. This is the historical Normalize behavior.
+	ProfileFilename Profile = iota
+	// ProfilePath splits the input on "/", drops "." and ".."
+	// components, normalizes each remaining segment like
+	// ProfileFilename, and rejoins the result with "/".
+	ProfilePath
+	// ProfileURLSlug slugs the entire input as one unit, with no
+	// extension splitting, for building URL path segments or slugs
+	// that aren't file names (e.g. blog post slugs).
+	ProfileURLSlug
+)
+
+// Form selects which Unicode normalization form runs before
+// transliteration, so compatibility characters (ligatures like "ﬁ",
+// full-width "Ａ", superscripts, non-breaking spaces) decompose into
+// the canonical characters the Transliterator expects.
+type Form int
+
+const (
+	// FormNFKC applies compatibility decomposition followed by
+	// canonical composition. This is Normalizer's default: it folds
+	// ligatures, full-width forms, and similar compatibility
+	// characters to their plain equivalents without altering
+	// already-canonical accented letters (those are left to the
+	// Transliterator).
+	FormNFKC Form = iota
+	// FormNFC applies canonical composition only; compatibility
+	// characters like ligatures and full-width forms are left as-is.
+	FormNFC
+	// FormNFKD applies compatibility decomposition without
+	// recomposing, leaving accented letters split into a base rune
+	// plus combining marks.
+	FormNFKD
+	// FormNone skips Unicode normalization entirely.
+	FormNone
+)
+
+// transform applies f to s.
+func (f Form) transform(s string) string {
+	switch f {
+	case FormNFC:
+		return norm.NFC.String(s)
+	case FormNFKD:
+		return norm.NFKD.String(s)
+	case FormNone:
+		return s
+	default:
+		return norm.NFKC.String(s)
+	}
+}
+
+// Transliterator folds or removes non-ASCII runes that survive a
+// Normalizer's Form step, before the forbidden-character pass replaces
+// whatever is still left with the separator.
+type Transliterator interface {
+	Transliterate(s string) string
+}
+
+// AsciiFoldTransliterator canonically decomposes s (NFD) and drops the
+// resulting combining marks (so "café" becomes "cafe"), leaving any
+// rune that still isn't ASCII for the forbidden-character pass to
+// replace with the separator. It uses canonical rather than
+// compatibility decomposition so it only folds accented letters,
+// leaving compatibility characters like ligatures to the Normalizer's
+// Form. This is Normalizer's default Transliterator.
+type AsciiFoldTransliterator struct{}
+
+func (AsciiFoldTransliterator) Transliterate(s string) string {
+	return nonSpacingMarks.String(norm.NFD.String(s))
+}
+
+// StripNonASCIITransliterator decomposes and drops combining marks
+// like AsciiFoldTransliterator, but also removes every rune that is
+// still non-ASCII afterward instead of leaving it for the
+// forbidden-character pass to replace with a separator, so
+// "café€notes" becomes "cafenotes" rather than "cafe-notes".
+type StripNonASCIITransliterator struct{}
+
+func (StripNonASCIITransliterator) Transliterate(s string) string {
+	decomposed := nonSpacingMarks.String(norm.NFD.String(s))
+	var b strings.Builder
+	for _, r := range decomposed {
+		if r > unicode.MaxASCII {
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// Normalizer converts a string to a safe, lowercase, hyphenated form
+// according to its Profile, replacement map, Form, Transliterator, and
+// any extra forbidden characters configured via its With... builder
+// methods. The zero value is not ready for use; build one with
+// DefaultNormalizer.
+type Normalizer struct {
+	profile                Profile
+	separator              string
+	replacements           map[string]string
+	forbidden              string
+	form                   Form
+	transliterator         Transliterator
+	preservePathSeparators bool
+}
+
+// DefaultNormalizer returns a Normalizer with the historical Normalize
+// behavior: ProfileFilename, a hyphen separator, the original
+// special-character replacements (/ -> or, & -> and, @ -> at, % -> percent),
+// FormNFKC, and an AsciiFoldTransliterator.
+func DefaultNormalizer() *Normalizer {
+	replacements := make(map[string]string, len(defaultReplacements))
+	for orig, repl := range defaultReplacements {
+		replacements[orig] = repl
+	}
+	return &Normalizer{
+		profile:        ProfileFilename,
+		separator:      spaceReplacer,
+		replacements:   replacements,
+		form:           FormNFKC,
+		transliterator: AsciiFoldTransliterator{},
+	}
+}
+
+// WithProfile returns a copy of n that slugs using profile instead of
+// its current one.
+func (n *Normalizer) WithProfile(profile Profile) *Normalizer {
+	clone := n.clone()
+	clone.profile = profile
+	return clone
+}
+
+// WithForm returns a copy of n that applies form before transliteration
+// instead of its current Form.
+func (n *Normalizer) WithForm(form Form) *Normalizer {
+	clone := n.clone()
+	clone.form = form
+	return clone
+}
+
+// WithTransliterator returns a copy of n that folds or removes
+// non-ASCII runes using t instead of its current Transliterator.
+func (n *Normalizer) WithTransliterator(t Transliterator) *Normalizer {
+	clone := n.clone()
+	clone.transliterator = t
+	return clone
+}
+
+// WithReplacement returns a copy of n that replaces every occurrence of
+// from with to before forbidden-character replacement runs, overriding
+// any existing replacement for from (including the defaults).
+func (n *Normalizer) WithReplacement(from, to string) *Normalizer {
+	clone := n.clone()
+	clone.replacements[from] = to
+	return clone
+}
+
+// WithForbidden returns a copy of n that always replaces each character
+// in chars with its separator, even characters (like "." or "_") the
+// default rules would otherwise let through unchanged.
+func (n *Normalizer) WithForbidden(chars string) *Normalizer {
+	clone := n.clone()
+	clone.forbidden += chars
+	return clone
+}
+
+// WithPreservePathSeparators returns a copy of n that leaves literal
+// "/" characters in its input alone instead of replacing them (by
+// default the special-character replacements turn "/" into "-or-").
+// Unlike ProfilePath, this doesn't split the input into
+// segments or drop "." / ".." components; it just stops ProfileFilename
+// and ProfileURLSlug from mangling a "/" that should survive as-is.
+func (n *Normalizer) WithPreservePathSeparators(preserve bool) *Normalizer {
+	clone := n.clone()
+	clone.preservePathSeparators = preserve
+	return clone
+}
+
+// clone returns a shallow copy of n with its own replacements map, so
+// With... methods can return a modified copy without mutating n.
+func (n *Normalizer) clone() *Normalizer {
+	replacements := make(map[string]string, len(n.replacements))
+	for from, to := range n.replacements {
+		replacements[from] = to
+	}
+	c := *n
+	c.replacements = replacements
+	return &c
+}
+
+// Normalize transforms s to a safe, lowercase, hyphenated form
+// according to n's Profile and overrides.
+func (n *Normalizer) Normalize(s string) string {
+	if n.profile == ProfilePath {
+		return n.normalizePath(s)
+	}
+	return n.normalizeSegment(s, n.profile == ProfileURLSlug)
+}
+
+// normalizePath implements ProfilePath: split on "/", drop "." and ".."
+// components, normalize each remaining segment, and rejoin with "/".
+func (n *Normalizer) normalizePath(s string) string {
+	segments := strings.Split(s, "/")
+	kept := make([]string, 0, len(segments))
+	for _, seg := range segments {
+		if seg == "." || seg == ".." {
+			continue
+		}
+		kept = append(kept, n.normalizeSegment(seg, false))
+	}
+	return strings.Join(kept, "/")
+}
+
+// normalizeSegment slugs a single path-free segment. If wholeString is
+// true (ProfileURLSlug), the whole segment is slugged as one unit with
+// no extension splitting; otherwise (ProfileFilename and ProfilePath's
+// per-segment calls) the segment's extension survives untouched.
+func (n *Normalizer) normalizeSegment(s string, wholeString bool) string {
+	ext := ""
+	nameOnly := s
+	if !wholeString {
+		ext = filepath.Ext(s)
+		nameOnly = strings.TrimSuffix(s, ext)
+	}
+
+	// 0. Apply the Unicode normalization form, so compatibility
+	// characters (ligatures, full-width forms, non-breaking spaces, ...)
+	// decompose to the canonical characters the later steps expect.
+	result := n.form.transform(nameOnly)
+
+	// 1. Replace spaces with the separator
+	result = strings.ReplaceAll(result, " ", n.separator)
+
+	// 2. Convert to lowercase
+	result = strings.ToLower(result)
+
+	// 3. Apply special character replacements
+	for orig, repl := range n.replacements {
+		if n.preservePathSeparators && orig == "/" {
+			continue
+		}
+		result = strings.ReplaceAll(result, orig, repl)
+	}
+
+	// 4. Replace any characters explicitly forbidden via WithForbidden,
+	// even ones the default allowed set wouldn't otherwise touch.
+	for _, r := range n.forbidden {
+		result = strings.ReplaceAll(result, string(r), n.separator)
+	}
+
+	// 5. Fold or remove remaining non-ASCII runes (e.g. accented
+	// letters) via the configured Transliterator.
+	result = n.transliterator.Transliterate(result)
+
+	// 6. Replace forbidden characters with the separator.
+	// Keep only: letters, numbers, hyphens, underscores, periods
+	// (and, with PreservePathSeparators, slashes).
+	forbiddenRe := forbiddenCharsRe
+	if n.preservePathSeparators {
+		forbiddenRe = forbiddenCharsAllowSlashRe
+	}
+	result = forbiddenRe.ReplaceAllString(result, n.separator)
+
+	// 7. Clean up multiple consecutive separators
+	result = multiHyphenRe.ReplaceAllString(result, n.separator)
+
+	// 8. Trim leading/trailing separators
+	result = strings.Trim(result, n.separator)
+
+	// Convert extension to lowercase too
+	ext = strings.ToLower(ext)
+
+	return result + ext
+}
+
+// Normalize transforms a filename to a safe, lowercase, hyphenated
+// form: spaces to hyphens, lowercase conversion, forbidden character
+// replacement, etc. It is a thin wrapper around
+// DefaultNormalizer().Normalize, kept for backward compatibility.
+func Normalize(filename string) string {
+	return DefaultNormalizer().Normalize(filename)
+}
+
+// NormalizeBytes decodes data as text before normalizing it, sniffing a
+// UTF-8, UTF-16LE, or UTF-16BE byte order mark in its first few bytes
+// and transcoding to UTF-8 if one is present. Input with no recognized
+// BOM is treated as UTF-8, matching Normalize byte-for-byte. Invalid
+// UTF-16 surrogate pairs decode to U+FFFD, which Normalize's forbidden
+// character pass then collapses to a hyphen like any other symbol.
+func NormalizeBytes(data []byte) string {
+	decoder := encunicode.BOMOverride(encunicode.UTF8.NewDecoder())
+	decoded, err := io.ReadAll(transform.NewReader(bytes.NewReader(data), decoder))
+	if err != nil {
+		return Normalize(string(data))
+	}
+	return Normalize(string(decoded))
+}
@@ -0,0 +1,249 @@
+package normalize
+
+import "testing"
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name:     "spaces replaced with hyphens",
+			input:    "My File.txt",
+			expected: "my-file.txt",
+		},
+		{
+			name:     "case converted to lowercase",
+			input:    "HELLO.txt",
+			expected: "hello.txt",
+		},
+		{
+			name:     "forbidden characters replaced",
+			input:    "file!name.txt",
+			expected: "file-name.txt",
+		},
+		{
+			name:     "slash replaced with or",
+			input:    "tcp/udp guide.md",
+			expected: "tcp-or-udp-guide.md",
+		},
+		{
+			name:     "multiple hyphens collapsed",
+			input:    "file--name---test.txt",
+			expected: "file-name-test.txt",
+		},
+		{
+			name:     "extension lowercased",
+			input:    "report.PDF",
+			expected: "report.pdf",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got := Normalize(tc.input)
+			if got != tc.expected {
+				t.Fatalf("expected %q, got %q", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestNormalizeUnicodeForms(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name:     "accented letter ascii-folded",
+			input:    "café.txt",
+			expected: "cafe.txt",
+		},
+		{
+			name:     "ligature decomposed by NFKC",
+			input:    "ﬀile.txt",
+			expected: "ffile.txt",
+		},
+		{
+			name:     "full-width digits decomposed by NFKC",
+			input:    "Ｆile１.txt",
+			expected: "file1.txt",
+		},
+		{
+			name:     "non-breaking space treated like a regular space",
+			input:    "My File.txt",
+			expected: "my-file.txt",
+		},
+		{
+			name:     "curly quotes and apostrophes still fall through to the separator",
+			input:    "rock’n’roll “ﬀile”.txt",
+			expected: "rock-n-roll-ffile.txt",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got := Normalize(tc.input)
+			if got != tc.expected {
+				t.Fatalf("expected %q, got %q", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestNormalizerWithForm(t *testing.T) {
+	// With FormNone, the ligature is never decomposed into ASCII "ff",
+	// so it falls to the forbidden-character pass, which replaces it
+	// with a separator that then gets trimmed as a leading hyphen.
+	n := DefaultNormalizer().WithForm(FormNone)
+	got := n.Normalize("ﬀile.txt")
+	want := "ile.txt"
+	if got != want {
+		t.Fatalf("expected %q, got %q", want, got)
+	}
+}
+
+func TestNormalizerWithTransliterator(t *testing.T) {
+	n := DefaultNormalizer().WithTransliterator(StripNonASCIITransliterator{})
+	got := n.Normalize("café€notes.txt")
+	want := "cafenotes.txt"
+	if got != want {
+		t.Fatalf("expected %q, got %q", want, got)
+	}
+}
+
+func TestNormalizerProfilePath(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name:     "segments normalized independently",
+			input:    "My Folder/Sub Folder/My File.txt",
+			expected: "my-folder/sub-folder/my-file.txt",
+		},
+		{
+			name:     "dot and dot-dot components dropped",
+			input:    "My Folder/./../My File.txt",
+			expected: "my-folder/my-file.txt",
+		},
+		{
+			name:     "leading slash preserved",
+			input:    "/My Folder/My File.txt",
+			expected: "/my-folder/my-file.txt",
+		},
+	}
+
+	n := DefaultNormalizer().WithProfile(ProfilePath)
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got := n.Normalize(tc.input)
+			if got != tc.expected {
+				t.Fatalf("expected %q, got %q", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestNormalizerProfileURLSlug(t *testing.T) {
+	n := DefaultNormalizer().WithProfile(ProfileURLSlug)
+	got := n.Normalize("My Great Post, v2.0!")
+	want := "my-great-post-v2.0"
+	if got != want {
+		t.Fatalf("expected %q, got %q", want, got)
+	}
+}
+
+func TestNormalizerWithReplacement(t *testing.T) {
+	n := DefaultNormalizer().WithReplacement("&", "et")
+	got := n.Normalize("Salt & Pepper.txt")
+	want := "salt-et-pepper.txt"
+	if got != want {
+		t.Fatalf("expected %q, got %q", want, got)
+	}
+}
+
+func TestNormalizerWithForbidden(t *testing.T) {
+	n := DefaultNormalizer().WithForbidden(".")
+	got := n.Normalize("file.name.v2.txt")
+	want := "file-name-v2.txt"
+	if got != want {
+		t.Fatalf("expected %q, got %q", want, got)
+	}
+}
+
+func TestNormalizerWithPreservePathSeparators(t *testing.T) {
+	n := DefaultNormalizer().WithPreservePathSeparators(true)
+	got := n.Normalize("Photos/Vacation Pics.txt")
+	want := "photos/vacation-pics.txt"
+	if got != want {
+		t.Fatalf("expected %q, got %q", want, got)
+	}
+
+	// Without the option, "/" still gets its default "-or-"
+	// replacement.
+	def := DefaultNormalizer().Normalize("Photos/Vacation Pics.txt")
+	if def == got {
+		t.Fatalf("expected default Normalize to differ from PreservePathSeparators, both got %q", def)
+	}
+}
+
+func TestNormalizeBytes(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    []byte
+		expected string
+	}{
+		{
+			name:     "no BOM treated as UTF-8",
+			input:    []byte("My File.txt"),
+			expected: "my-file.txt",
+		},
+		{
+			name:     "UTF-8 BOM stripped",
+			input:    append([]byte{0xEF, 0xBB, 0xBF}, []byte("My File.txt")...),
+			expected: "my-file.txt",
+		},
+		{
+			name:     "UTF-16LE BOM decoded",
+			input:    append([]byte{0xFF, 0xFE}, encodeUTF16LE("My File.txt")...),
+			expected: "my-file.txt",
+		},
+		{
+			name:     "UTF-16BE BOM decoded",
+			input:    append([]byte{0xFE, 0xFF}, encodeUTF16BE("My File.txt")...),
+			expected: "my-file.txt",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got := NormalizeBytes(tc.input)
+			if got != tc.expected {
+				t.Fatalf("expected %q, got %q", tc.expected, got)
+			}
+		})
+	}
+}
+
+// encodeUTF16LE encodes s as raw little-endian UTF-16 code units, for
+// building BOM-prefixed test fixtures.
+func encodeUTF16LE(s string) []byte {
+	var buf []byte
+	for _, r := range s {
+		buf = append(buf, byte(r), byte(r>>8))
+	}
+	return buf
+}
+
+// encodeUTF16BE encodes s as raw big-endian UTF-16 code units.
+func encodeUTF16BE(s string) []byte {
+	var buf []byte
+	for _, r := range s {
+		buf = append(buf, byte(r>>8), byte(r))
+	}
+	return buf
+}
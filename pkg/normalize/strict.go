@@ -0,0 +1,166 @@
+package normalize
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// reservedChars are the characters Windows forbids in a file name.
+const reservedChars = `<>:"|?*`
+
+// reservedDeviceNames are the DOS device names Windows reserves
+// regardless of extension (CON.txt is just as reserved as CON).
+var reservedDeviceNames = map[string]bool{
+	"CON": true, "PRN": true, "AUX": true, "NUL": true,
+}
+
+// Violation describes a single cross-platform filesystem safety
+// problem NormalizeStrict found in an input name.
+type Violation struct {
+	// Rule identifies which check failed: "control-byte",
+	// "reserved-char", "reserved-name", or "trailing-dot-or-space".
+	Rule string
+	// Detail gives the offending character or name.
+	Detail string
+}
+
+func (v Violation) String() string {
+	return fmt.Sprintf("%s %q", v.Rule, v.Detail)
+}
+
+// StrictError reports every Violation NormalizeStrict found in an
+// input, so a caller can choose to use the sanitized name anyway
+// (force) or skip the input instead.
+type StrictError struct {
+	Violations []Violation
+}
+
+func (e *StrictError) Error() string {
+	parts := make([]string, len(e.Violations))
+	for i, v := range e.Violations {
+		parts[i] = v.String()
+	}
+	return "unsafe filename: " + strings.Join(parts, "; ")
+}
+
+// CollisionError reports that two or more inputs to NormalizeBatch
+// produced the same case-folded result, which would silently clobber
+// one file with another on a case-insensitive filesystem.
+type CollisionError struct {
+	// Result is the shared, case-folded output the colliding inputs
+	// normalized to.
+	Result string
+	// Inputs lists every original input that produced Result, in the
+	// order they were passed to NormalizeBatch.
+	Inputs []string
+}
+
+func (e *CollisionError) Error() string {
+	return fmt.Sprintf("inputs %s all normalize to %q", strings.Join(quoteAll(e.Inputs), ", "), e.Result)
+}
+
+func quoteAll(ss []string) []string {
+	quoted := make([]string, len(ss))
+	for i, s := range ss {
+		quoted[i] = fmt.Sprintf("%q", s)
+	}
+	return quoted
+}
+
+// NormalizeStrict normalizes s like Normalize, then enforces
+// cross-platform filesystem safety: the Windows-reserved characters
+// <>:"|?* and control bytes (0x00-0x1F) are already replaced with the
+// separator by Normalize's forbidden-character pass; on top of that,
+// NormalizeStrict trims trailing dots and spaces from the base name
+// and suffixes "-file" onto a base name that matches a reserved DOS
+// device name (CON, PRN, AUX, NUL, COM1-9, LPT1-9). It returns the
+// sanitized result together with a *StrictError listing every
+// violation found in s, so a caller can use the sanitized name anyway
+// (force) or skip the input instead.
+func NormalizeStrict(s string) (string, error) {
+	violations := detectViolations(s)
+
+	result := strings.TrimRight(Normalize(s), ". ")
+	ext := filepath.Ext(result)
+	base := strings.TrimSuffix(result, ext)
+	if isReservedDeviceName(base) {
+		base += "-file"
+	}
+	result = base + ext
+
+	if len(violations) == 0 {
+		return result, nil
+	}
+	return result, &StrictError{Violations: violations}
+}
+
+// NormalizeBatch normalizes every input with Normalize and returns the
+// results in order. It returns a *CollisionError if two or more inputs
+// produce the same case-folded result, since that would overwrite one
+// renamed file with another on a case-insensitive filesystem; the
+// normalized results are still returned alongside the error so a
+// caller can decide how to resolve the collision.
+func NormalizeBatch(inputs []string) ([]string, error) {
+	results := make([]string, len(inputs))
+	indices := make(map[string][]int, len(inputs))
+	for i, s := range inputs {
+		results[i] = Normalize(s)
+		key := strings.ToLower(results[i])
+		indices[key] = append(indices[key], i)
+	}
+
+	reported := make(map[string]bool, len(indices))
+	for i := range inputs {
+		key := strings.ToLower(results[i])
+		if len(indices[key]) < 2 || reported[key] {
+			continue
+		}
+		reported[key] = true
+		colliding := make([]string, len(indices[key]))
+		for j, idx := range indices[key] {
+			colliding[j] = inputs[idx]
+		}
+		return results, &CollisionError{Result: results[i], Inputs: colliding}
+	}
+	return results, nil
+}
+
+// detectViolations scans the original (pre-normalization) input for
+// every cross-platform safety problem NormalizeStrict checks for.
+func detectViolations(s string) []Violation {
+	var violations []Violation
+	for _, r := range s {
+		switch {
+		case r < 0x20:
+			violations = append(violations, Violation{Rule: "control-byte", Detail: fmt.Sprintf("0x%02X", r)})
+		case strings.ContainsRune(reservedChars, r):
+			violations = append(violations, Violation{Rule: "reserved-char", Detail: string(r)})
+		}
+	}
+
+	base := strings.TrimSuffix(s, filepath.Ext(s))
+	if isReservedDeviceName(base) {
+		violations = append(violations, Violation{Rule: "reserved-name", Detail: base})
+	}
+	if trimmed := strings.TrimRight(s, ". "); trimmed != s {
+		violations = append(violations, Violation{Rule: "trailing-dot-or-space", Detail: s})
+	}
+
+	return violations
+}
+
+// isReservedDeviceName reports whether name (case-insensitively)
+// matches a DOS device name Windows reserves: CON, PRN, AUX, NUL,
+// COM1-9, or LPT1-9.
+func isReservedDeviceName(name string) bool {
+	upper := strings.ToUpper(name)
+	if reservedDeviceNames[upper] {
+		return true
+	}
+	if len(upper) != 4 {
+		return false
+	}
+	prefix, digit := upper[:3], upper[3]
+	return (prefix == "COM" || prefix == "LPT") && digit >= '1' && digit <= '9'
+}
@@ -0,0 +1,129 @@
+package normalize
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestNormalizeStrict(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+		wantErr  bool
+		wantRule string
+	}{
+		{
+			name:     "clean name has no violations",
+			input:    "My File.txt",
+			expected: "my-file.txt",
+			wantErr:  false,
+		},
+		{
+			name:     "reserved characters reported and replaced",
+			input:    "report<final>.txt",
+			expected: "report-final.txt",
+			wantErr:  true,
+			wantRule: "reserved-char",
+		},
+		{
+			name:     "control byte reported and replaced",
+			input:    "bad\x07name.txt",
+			expected: "bad-name.txt",
+			wantErr:  true,
+			wantRule: "control-byte",
+		},
+		{
+			name:     "reserved device name suffixed",
+			input:    "CON.txt",
+			expected: "con-file.txt",
+			wantErr:  true,
+			wantRule: "reserved-name",
+		},
+		{
+			name:     "reserved device name is case-insensitive",
+			input:    "com1.txt",
+			expected: "com1-file.txt",
+			wantErr:  true,
+			wantRule: "reserved-name",
+		},
+		{
+			name:     "trailing dot trimmed",
+			input:    "notes.",
+			expected: "notes",
+			wantErr:  true,
+			wantRule: "trailing-dot-or-space",
+		},
+		{
+			name:     "lookalike device name left alone",
+			input:    "console.txt",
+			expected: "console.txt",
+			wantErr:  false,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := NormalizeStrict(tc.input)
+			if got != tc.expected {
+				t.Fatalf("expected %q, got %q", tc.expected, got)
+			}
+			if tc.wantErr && err == nil {
+				t.Fatalf("expected an error, got nil")
+			}
+			if !tc.wantErr && err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if tc.wantErr {
+				var strictErr *StrictError
+				if !errors.As(err, &strictErr) {
+					t.Fatalf("expected *StrictError, got %T", err)
+				}
+				found := false
+				for _, v := range strictErr.Violations {
+					if v.Rule == tc.wantRule {
+						found = true
+					}
+				}
+				if !found {
+					t.Fatalf("expected a violation with rule %q, got %v", tc.wantRule, strictErr.Violations)
+				}
+			}
+		})
+	}
+}
+
+func TestNormalizeBatch(t *testing.T) {
+	t.Run("no collisions", func(t *testing.T) {
+		results, err := NormalizeBatch([]string{"My File.txt", "Other File.txt"})
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		want := []string{"my-file.txt", "other-file.txt"}
+		for i, w := range want {
+			if results[i] != w {
+				t.Fatalf("expected %q, got %q", w, results[i])
+			}
+		}
+	})
+
+	t.Run("case-fold collision detected", func(t *testing.T) {
+		results, err := NormalizeBatch([]string{"Report.txt", "report.txt", "REPORT.txt"})
+		if err == nil {
+			t.Fatalf("expected a collision error, got nil")
+		}
+		var collErr *CollisionError
+		if !errors.As(err, &collErr) {
+			t.Fatalf("expected *CollisionError, got %T", err)
+		}
+		if collErr.Result != "report.txt" {
+			t.Fatalf("expected collision result %q, got %q", "report.txt", collErr.Result)
+		}
+		if len(collErr.Inputs) != 3 {
+			t.Fatalf("expected 3 colliding inputs, got %v", collErr.Inputs)
+		}
+		if len(results) != 3 {
+			t.Fatalf("expected results still returned for all inputs, got %v", results)
+		}
+	})
+}